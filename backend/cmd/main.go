@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -12,7 +13,12 @@ import (
 	"testbox/internal/database"
 	"testbox/internal/messaging"
 	"testbox/internal/repository"
+	"testbox/internal/scheduler"
+	"testbox/internal/search"
 	"testbox/internal/service"
+	"testbox/internal/storage"
+	"testbox/internal/workflow"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -54,18 +60,85 @@ func main() {
 		defer mongoDB.Close()
 	}
 
+	// 백그라운드 작업 스케줄러 초기화 (작업은 PostgreSQL에 저장됩니다)
+	jobRepo := repository.NewJobRepository(postgresDB.DB)
+	jobScheduler := scheduler.NewScheduler(jobRepo, cfg.SchedulerInterval)
+
+	// Todo 워크플로 (상태 및 허용 전환) 로드
+	todoWorkflow, err := workflow.Parse(cfg.WorkflowSpec, cfg.WorkflowInitial)
+	if err != nil {
+		log.Fatalf("워크플로 설정 오류: %v", err)
+	}
+
 	// 각 레이어 초기화
 	todoRepo := repository.NewTodoRepository(postgresDB.DB)
-	todoService := service.NewTodoService(todoRepo, redisCache, rabbitMQ)
-	todoHandler := api.NewTodoHandler(todoService)
+	listRepo := repository.NewListRepository(postgresDB.DB)
+	tagRepo := repository.NewTagRepository(postgresDB.DB)
+	depRepo := repository.NewDependencyRepository(postgresDB.DB)
+	activityRepo := repository.NewActivityRepository(postgresDB.DB)
+	revisionRepo := repository.NewRevisionRepository(postgresDB.DB)
+	todoService := service.NewTodoService(todoRepo, listRepo, tagRepo, depRepo, activityRepo, revisionRepo, redisCache, rabbitMQ, jobScheduler, todoWorkflow, cfg.ReminderLead, cfg.ArchiveAfter)
+	undoService := service.NewUndoService(todoRepo, todoService, redisCache, cfg.UndoWindow)
+	todoHandler := api.NewTodoHandler(todoService, undoService)
+	undoHandler := api.NewUndoHandler(undoService)
+
+	listService := service.NewListService(listRepo, todoRepo, redisCache, rabbitMQ)
+	listHandler := api.NewListHandler(listService, todoService)
 
 	blogRepo := repository.NewBlogRepository(postgresDB.DB)
-	blogService := service.NewBlogService(blogRepo, redisCache, rabbitMQ)
+	blogService := service.NewBlogService(blogRepo, tagRepo, revisionRepo, redisCache, rabbitMQ, jobScheduler)
 	blogHandler := api.NewBlogHandler(blogService)
 
+	timeEntryRepo := repository.NewTimeEntryRepository(postgresDB.DB)
+	timeEntryService := service.NewTimeEntryService(timeEntryRepo, todoRepo, rabbitMQ)
+	timeEntryHandler := api.NewTimeEntryHandler(timeEntryService)
+
+	commentRepo := repository.NewCommentRepository(postgresDB.DB)
+	commentService := service.NewCommentService(commentRepo, activityRepo, todoRepo, rabbitMQ)
+	commentHandler := api.NewCommentHandler(commentService)
+
+	// 첨부 파일 저장소 (local 또는 S3 호환)
+	fileStorage, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("첨부 파일 저장소 초기화 실패: %v", err)
+	}
+	attachmentRepo := repository.NewAttachmentRepository(postgresDB.DB)
+	attachmentService := service.NewAttachmentService(attachmentRepo, todoRepo, blogRepo, fileStorage, rabbitMQ, jobScheduler, service.AttachmentLimits{
+		MaxSize:      cfg.AttachmentMaxSize,
+		AllowedTypes: cfg.AttachmentAllowedTypes,
+	})
+	attachmentHandler := api.NewAttachmentHandler(attachmentService, blogService)
+
+	trashService := service.NewTrashService(todoRepo, blogRepo, jobScheduler, cfg.TrashRetention)
+	trashHandler := api.NewTrashHandler(trashService, todoService, blogService)
+
+	revisionService := service.NewRevisionService(revisionRepo, todoRepo, blogRepo)
+	revisionHandler := api.NewRevisionHandler(revisionService, todoService, blogService)
+
+	templateRepo := repository.NewTemplateRepository(postgresDB.DB)
+	templateService := service.NewTemplateService(templateRepo, todoService)
+	templateHandler := api.NewTemplateHandler(templateService)
+
+	filterRepo := repository.NewFilterRepository(postgresDB.DB)
+	viewService := service.NewViewService(filterRepo, todoRepo)
+	viewHandler := api.NewViewHandler(viewService)
+
+	// 검색 백엔드 (postgres 또는 memory)
+	searcher, err := search.New(cfg, postgresDB.DB, todoRepo, blogRepo, rabbitMQ)
+	if err != nil {
+		log.Fatalf("검색 백엔드 초기화 실패: %v", err)
+	}
+	searchService := service.NewSearchService(searcher)
+	searchHandler := api.NewSearchHandler(searchService)
+
+	tagService := service.NewTagService(tagRepo, redisCache, rabbitMQ)
+	tagHandler := api.NewTagHandler(tagService)
+
 	// Fiber 앱 생성
 	app := fiber.New(fiber.Config{
 		AppName: "Testbox Backend v1.0",
+		// 첨부 파일 업로드를 위해 multipart 오버헤드를 더한 만큼 허용합니다
+		BodyLimit: int(cfg.AttachmentMaxSize) + 1<<20,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			if e, ok := err.(*fiber.Error); ok {
@@ -83,12 +156,13 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "*",
 		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept",
+		AllowHeaders:     "Origin,Content-Type,Accept,X-User-ID",
+		ExposeHeaders:    "X-Undo-Token",
 		AllowCredentials: false,
 	}))
 
 	// 라우트 설정
-	api.SetupRoutes(app, todoHandler, blogHandler)
+	api.SetupRoutes(app, todoHandler, blogHandler, listHandler, tagHandler, timeEntryHandler, commentHandler, attachmentHandler, trashHandler, revisionHandler, undoHandler, templateHandler, viewHandler, searchHandler)
 
 	// Graceful Shutdown 설정
 	c := make(chan os.Signal, 1)
@@ -97,9 +171,20 @@ func main() {
 	go func() {
 		<-c
 		log.Println("서버를 안전하게 종료하는 중...")
+
+		// 실행 중인 예약 작업이 끝날 때까지 잠시 기다립니다
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := jobScheduler.Stop(ctx); err != nil {
+			log.Printf("경고: 스케줄러 종료 실패: %v", err)
+		}
+
 		app.Shutdown()
 	}()
 
+	// 스케줄러 시작 (모든 서비스가 작업 핸들러를 등록한 뒤)
+	jobScheduler.Start()
+
 	// 서버 시작
 	addr := fmt.Sprintf(":%s", cfg.ServerPort)
 	log.Printf("🚀 서버 시작: http://localhost%s", addr)
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+	"testbox/internal/models"
+	"testbox/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AttachmentHandler struct {
+	service     service.AttachmentService
+	blogService service.BlogService
+}
+
+func NewAttachmentHandler(service service.AttachmentService, blogService service.BlogService) *AttachmentHandler {
+	return &AttachmentHandler{service: service, blogService: blogService}
+}
+
+// UploadTodoAttachment attaches a file to a todo
+// @Summary Upload a todo attachment
+// @Description Uploads a file (multipart field "file"); size and MIME type are limited by configuration
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param file formData file true "File to upload"
+// @Success 201 {object} models.Attachment
+// @Router /api/todos/{id}/attachments [post]
+func (h *AttachmentHandler) UploadTodoAttachment(c *fiber.Ctx) error {
+	return h.upload(c, models.AttachmentOwnerTodo)
+}
+
+// UploadBlogAttachment attaches a file to a blog post
+// @Summary Upload a blog post attachment
+// @Description Uploads a file (multipart field "file"); size and MIME type are limited by configuration
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Blog post ID"
+// @Param file formData file true "File to upload"
+// @Success 201 {object} models.Attachment
+// @Router /api/blogs/{id}/attachments [post]
+func (h *AttachmentHandler) UploadBlogAttachment(c *fiber.Ctx) error {
+	return h.upload(c, models.AttachmentOwnerBlog)
+}
+
+// GetTodoAttachments lists the attachments of a todo
+// @Summary Get todo attachments
+// @Description Lists the files attached to a todo
+// @Tags attachments
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {array} models.Attachment
+// @Router /api/todos/{id}/attachments [get]
+func (h *AttachmentHandler) GetTodoAttachments(c *fiber.Ctx) error {
+	return h.list(c, models.AttachmentOwnerTodo)
+}
+
+// GetBlogAttachments lists the attachments of a blog post
+// @Summary Get blog post attachments
+// @Description Lists the files attached to a blog post
+// @Tags attachments
+// @Produce json
+// @Param id path string true "Blog post ID"
+// @Success 200 {array} models.Attachment
+// @Router /api/blogs/{id}/attachments [get]
+func (h *AttachmentHandler) GetBlogAttachments(c *fiber.Ctx) error {
+	return h.list(c, models.AttachmentOwnerBlog)
+}
+
+// GetAttachment retrieves attachment metadata
+// @Summary Get attachment metadata
+// @Description Retrieves the file name, type and size of an attachment
+// @Tags attachments
+// @Produce json
+// @Param id path string true "Attachment ID"
+// @Success 200 {object} models.Attachment
+// @Router /api/attachments/{id} [get]
+func (h *AttachmentHandler) GetAttachment(c *fiber.Ctx) error {
+	attachment, err := h.service.GetAttachment(c.Params("id"))
+	if err == nil {
+		err = h.checkReadable(c, attachment.OwnerType, attachment.OwnerID)
+	}
+	if err != nil {
+		return c.Status(attachmentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(attachment)
+}
+
+// DownloadAttachment streams the attachment's content
+// @Summary Download an attachment
+// @Description Streams the file with its Content-Type; a single "Range: bytes=..." request returns 206 Partial Content
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path string true "Attachment ID"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
+// @Success 200
+// @Success 206
+// @Router /api/attachments/{id}/download [get]
+func (h *AttachmentHandler) DownloadAttachment(c *fiber.Ctx) error {
+	attachment, err := h.service.GetAttachment(c.Params("id"))
+	if err == nil {
+		err = h.checkReadable(c, attachment.OwnerType, attachment.OwnerID)
+	}
+	if err != nil {
+		return c.Status(attachmentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	offset, length, status := parseByteRange(c.Get(fiber.HeaderRange), attachment.Size)
+	if status == fiber.StatusRequestedRangeNotSatisfiable {
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", attachment.Size))
+		return c.Status(status).JSON(fiber.Map{
+			"error": "Requested range not satisfiable",
+		})
+	}
+
+	reader, err := h.service.Open(c.UserContext(), attachment, offset, length)
+	if err != nil {
+		return c.Status(attachmentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") || strings.HasPrefix(attachment.ContentType, "application/pdf") {
+		disposition = "inline"
+	}
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	if status == fiber.StatusPartialContent {
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, attachment.Size))
+	}
+
+	return c.Status(status).SendStream(reader, int(length))
+}
+
+// DeleteAttachment deletes an attachment and its stored file
+// @Summary Delete an attachment
+// @Description Deletes the attachment record and removes the file from storage
+// @Tags attachments
+// @Param id path string true "Attachment ID"
+// @Success 204
+// @Router /api/attachments/{id} [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *fiber.Ctx) error {
+	if err := h.service.DeleteAttachment(c.Params("id")); err != nil {
+		return c.Status(attachmentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// upload reads the multipart "file" field and stores it for the owner in the :id path parameter
+func (h *AttachmentHandler) upload(c *fiber.Ctx, ownerType string) error {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Multipart field \"file\" is required",
+		})
+	}
+	if header.Size == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "File is empty",
+		})
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Failed to read uploaded file",
+		})
+	}
+	defer file.Close()
+
+	attachment, err := h.service.Upload(ownerType, c.Params("id"), currentUserID(c), header.Filename, file, header.Size)
+	if err != nil {
+		return c.Status(attachmentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(attachment)
+}
+
+// list returns the attachments of the owner in the :id path parameter
+func (h *AttachmentHandler) list(c *fiber.Ctx, ownerType string) error {
+	if err := h.checkReadable(c, ownerType, c.Params("id")); err != nil {
+		return c.Status(attachmentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	attachments, err := h.service.GetAttachments(ownerType, c.Params("id"))
+	if err != nil {
+		return c.Status(attachmentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(attachments)
+}
+
+// checkReadable hides the files of blog posts the request may not read
+func (h *AttachmentHandler) checkReadable(c *fiber.Ctx, ownerType, ownerID string) error {
+	if ownerType != models.AttachmentOwnerBlog {
+		return nil
+	}
+	return checkBlogReadable(c, h.blogService, ownerID)
+}
+
+// parseByteRange interprets a Range header for a file of the given size and returns
+// the offset, length and response status. Missing, malformed and multi-range headers
+// fall back to the whole file (200), as RFC 9110 allows.
+func parseByteRange(header string, size int64) (int64, int64, int) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, size, fiber.StatusOK
+	}
+
+	startRaw, endRaw, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, size, fiber.StatusOK
+	}
+
+	// bytes=-N 은 마지막 N 바이트입니다
+	if startRaw == "" {
+		n, err := strconv.ParseInt(endRaw, 10, 64)
+		if err != nil || n < 0 {
+			return 0, size, fiber.StatusOK
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, fiber.StatusRequestedRangeNotSatisfiable
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, fiber.StatusPartialContent
+	}
+
+	start, err := strconv.ParseInt(startRaw, 10, 64)
+	if err != nil || start < 0 {
+		return 0, size, fiber.StatusOK
+	}
+	end := size - 1
+	if endRaw != "" {
+		end, err = strconv.ParseInt(endRaw, 10, 64)
+		if err != nil || end < start {
+			return 0, size, fiber.StatusOK
+		}
+		if end > size-1 {
+			end = size - 1
+		}
+	}
+	if start >= size {
+		return 0, 0, fiber.StatusRequestedRangeNotSatisfiable
+	}
+
+	return start, end - start + 1, fiber.StatusPartialContent
+}
+
+// attachmentErrorStatus maps attachment errors to HTTP status codes
+func attachmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrAttachmentNotFound), errors.Is(err, service.ErrAttachmentOwnerNotFound),
+		errors.Is(err, service.ErrBlogPostNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		return fiber.StatusRequestEntityTooLarge
+	case errors.Is(err, service.ErrAttachmentTypeNotAllowed):
+		return fiber.StatusUnsupportedMediaType
+	}
+	return fiber.StatusInternalServerError
+}
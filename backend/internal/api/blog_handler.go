@@ -1,7 +1,12 @@
 package api
 
 import (
+	"errors"
+	"net/url"
+	"strings"
+	"testbox/internal/models"
 	"testbox/internal/service"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -15,20 +20,27 @@ func NewBlogHandler(service service.BlogService) *BlogHandler {
 }
 
 type CreateBlogRequest struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Tags    string `json:"tags"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	Tags        []string   `json:"tags"`
+	Status      string     `json:"status"`       // draft, scheduled, published (default) or unlisted
+	PublishedAt *time.Time `json:"published_at"` // required for scheduled posts
 }
 
 type UpdateBlogRequest struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Tags    string `json:"tags"`
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"` // omitted keeps the current tags
+}
+
+type ChangeBlogStatusRequest struct {
+	Status      string     `json:"status"`
+	PublishedAt *time.Time `json:"published_at"` // required for scheduled posts
 }
 
 // CreateBlog creates a new blog post
 // @Summary Create a new blog post
-// @Description Creates a new blog post with title, content, and tags
+// @Description Creates a new blog post with title, content, and tags. Posts are published immediately unless status is draft, scheduled (with a future published_at) or unlisted.
 // @Tags blogs
 // @Accept json
 // @Produce json
@@ -54,9 +66,10 @@ func (h *BlogHandler) CreateBlog(c *fiber.Ctx) error {
 		})
 	}
 
-	blog, err := h.service.CreateBlogPost(req.Title, req.Content, req.Tags)
+	publication := service.BlogPublication{Status: req.Status, PublishedAt: req.PublishedAt}
+	blog, err := h.service.CreateBlogPost(req.Title, req.Content, req.Tags, publication, currentUserID(c))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return c.Status(blogErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
@@ -66,7 +79,7 @@ func (h *BlogHandler) CreateBlog(c *fiber.Ctx) error {
 
 // GetBlog retrieves a blog post by ID
 // @Summary Get blog post by ID
-// @Description Retrieves a specific blog post by its ID
+// @Description Retrieves a specific blog post by its ID. Anonymous readers only see published and unlisted posts.
 // @Tags blogs
 // @Produce json
 // @Param id path string true "Blog Post ID"
@@ -76,6 +89,9 @@ func (h *BlogHandler) GetBlog(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	blog, err := h.service.GetBlogPost(id)
+	if err == nil && !canRead(c, blog) {
+		err = service.ErrBlogPostNotFound
+	}
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": err.Error(),
@@ -85,17 +101,58 @@ func (h *BlogHandler) GetBlog(c *fiber.Ctx) error {
 	return c.JSON(blog)
 }
 
+// GetBlogBySlug retrieves a blog post by its slug
+// @Summary Get blog post by slug
+// @Description Retrieves a blog post by the slug derived from its title. Former slugs of renamed posts redirect (301) to the current slug. Anonymous readers only see published and unlisted posts.
+// @Tags blogs
+// @Produce json
+// @Param slug path string true "Blog post slug (percent-encoded)"
+// @Success 200 {object} models.BlogPost
+// @Success 301
+// @Router /api/blogs/by-slug/{slug} [get]
+func (h *BlogHandler) GetBlogBySlug(c *fiber.Ctx) error {
+	slug, err := url.PathUnescape(c.Params("slug"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid slug",
+		})
+	}
+	slug = strings.ToLower(slug)
+
+	blog, err := h.service.GetBlogPostBySlug(slug)
+	if err == nil && !canRead(c, blog) {
+		err = service.ErrBlogPostNotFound
+	}
+	if err != nil {
+		return c.Status(blogErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if blog.Slug != slug {
+		return c.Redirect("/api/blogs/by-slug/"+url.PathEscape(blog.Slug), fiber.StatusMovedPermanently)
+	}
+	return c.JSON(blog)
+}
+
 // GetAllBlogs retrieves all blog posts
 // @Summary Get all blog posts
-// @Description Retrieves all blog posts ordered by creation date (newest first)
+// @Description Retrieves blog posts ordered by publication date (newest first), optionally filtered by tags. Anonymous readers (no X-User-ID) only get published posts; identified users get every post or those in the given status.
 // @Tags blogs
 // @Produce json
+// @Param tags query string false "Comma-separated tag names; posts must carry all of them"
+// @Param status query string false "draft, scheduled, published or unlisted (identified users only)"
 // @Success 200 {array} models.BlogPost
 // @Router /api/blogs [get]
 func (h *BlogHandler) GetAllBlogs(c *fiber.Ctx) error {
-	blogs, err := h.service.GetAllBlogPosts()
+	status := c.Query("status")
+	if currentUserID(c) == "" {
+		status = models.BlogPublished
+	}
+
+	blogs, err := h.service.GetAllBlogPosts(parseTagQuery(c.Query("tags")), status)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return c.Status(blogErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
@@ -134,7 +191,7 @@ func (h *BlogHandler) UpdateBlog(c *fiber.Ctx) error {
 		})
 	}
 
-	blog, err := h.service.UpdateBlogPost(id, req.Title, req.Content, req.Tags)
+	blog, err := h.service.UpdateBlogPost(id, req.Title, req.Content, req.Tags, currentUserID(c))
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": err.Error(),
@@ -144,6 +201,41 @@ func (h *BlogHandler) UpdateBlog(c *fiber.Ctx) error {
 	return c.JSON(blog)
 }
 
+// ChangeBlogStatus changes the publication status of a blog post
+// @Summary Change blog post status
+// @Description Moves a post between draft, scheduled, published and unlisted. Scheduled posts need a future published_at and are published automatically at that time; every post that becomes published emits a blog_published event.
+// @Tags blogs
+// @Accept json
+// @Produce json
+// @Param id path string true "Blog Post ID"
+// @Success 200 {object} models.BlogPost
+// @Router /api/blogs/{id}/status [post]
+func (h *BlogHandler) ChangeBlogStatus(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req ChangeBlogStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.Status == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Status is required",
+		})
+	}
+
+	blog, err := h.service.ChangeBlogStatus(id, service.BlogPublication{Status: req.Status, PublishedAt: req.PublishedAt})
+	if err != nil {
+		return c.Status(blogErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(blog)
+}
+
 // DeleteBlog deletes a blog post
 // @Summary Delete blog post
 // @Description Deletes a blog post by ID
@@ -162,3 +254,35 @@ func (h *BlogHandler) DeleteBlog(c *fiber.Ctx) error {
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// canRead reports whether the request may see the post: drafts and scheduled posts
+// are hidden from anonymous readers
+func canRead(c *fiber.Ctx, blog *models.BlogPost) bool {
+	return blog.IsPublic() || currentUserID(c) != ""
+}
+
+// checkBlogReadable returns service.ErrBlogPostNotFound when the post does not exist or
+// is hidden from the request, so that its revisions and attachments stay private as well
+func checkBlogReadable(c *fiber.Ctx, blogs service.BlogService, id string) error {
+	if currentUserID(c) != "" {
+		return nil
+	}
+	blog, err := blogs.GetBlogPost(id)
+	if err != nil {
+		return err
+	}
+	if !canRead(c, blog) {
+		return service.ErrBlogPostNotFound
+	}
+	return nil
+}
+
+func blogErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, service.ErrBlogPostNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrInvalidBlogStatus), errors.Is(err, service.ErrInvalidPublishTime):
+		return fiber.StatusBadRequest
+	}
+	return fallback
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testbox/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxCommentLength bounds the size of a single comment
+const maxCommentLength = 10000
+
+type CommentHandler struct {
+	service service.CommentService
+}
+
+func NewCommentHandler(service service.CommentService) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+type CommentRequest struct {
+	Body string `json:"body"`
+}
+
+// validate trims the body and returns an error message if it is unusable
+func (req *CommentRequest) validate() string {
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" {
+		return "Body is required"
+	}
+	if len([]rune(req.Body)) > maxCommentLength {
+		return "Body must be at most 10000 characters"
+	}
+	return ""
+}
+
+// CreateComment adds a comment to a todo
+// @Summary Create a comment
+// @Description Adds a comment by the current user (X-User-ID header) and publishes a comment_created event
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param X-User-ID header string true "User ID"
+// @Success 201 {object} models.Comment
+// @Router /api/todos/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *fiber.Ctx) error {
+	author := currentUserID(c)
+	if author == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "X-User-ID header is required",
+		})
+	}
+
+	var req CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if msg := req.validate(); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	comment, err := h.service.CreateComment(c.Params("id"), author, req.Body)
+	if err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(comment)
+}
+
+// GetComments retrieves the comments of a todo
+// @Summary Get comments of a todo
+// @Description Retrieves all comments of the todo, oldest first
+// @Tags comments
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {array} models.Comment
+// @Router /api/todos/{id}/comments [get]
+func (h *CommentHandler) GetComments(c *fiber.Ctx) error {
+	comments, err := h.service.GetComments(c.Params("id"))
+	if err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(comments)
+}
+
+// UpdateComment edits a comment
+// @Summary Update a comment
+// @Description Changes the body of a comment; only its author may edit it
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param commentId path string true "Comment ID"
+// @Param X-User-ID header string true "User ID"
+// @Success 200 {object} models.Comment
+// @Router /api/todos/{id}/comments/{commentId} [put]
+func (h *CommentHandler) UpdateComment(c *fiber.Ctx) error {
+	author := currentUserID(c)
+	if author == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "X-User-ID header is required",
+		})
+	}
+
+	var req CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if msg := req.validate(); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	comment, err := h.service.UpdateComment(c.Params("id"), c.Params("commentId"), author, req.Body)
+	if err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(comment)
+}
+
+// DeleteComment deletes a comment
+// @Summary Delete a comment
+// @Description Deletes a comment; only its author may delete it
+// @Tags comments
+// @Param id path string true "Todo ID"
+// @Param commentId path string true "Comment ID"
+// @Param X-User-ID header string true "User ID"
+// @Success 204
+// @Router /api/todos/{id}/comments/{commentId} [delete]
+func (h *CommentHandler) DeleteComment(c *fiber.Ctx) error {
+	author := currentUserID(c)
+	if author == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "X-User-ID header is required",
+		})
+	}
+
+	if err := h.service.DeleteComment(c.Params("id"), c.Params("commentId"), author); err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetTimeline retrieves the merged activity timeline of a todo
+// @Summary Get the activity timeline of a todo
+// @Description Retrieves comments and recorded changes (creation, status changes, moves, dependencies) in chronological order
+// @Tags comments
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {array} models.TimelineItem
+// @Router /api/todos/{id}/activity [get]
+func (h *CommentHandler) GetTimeline(c *fiber.Ctx) error {
+	items, err := h.service.GetTimeline(c.Params("id"))
+	if err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(items)
+}
+
+// commentErrorStatus maps comment errors to HTTP status codes
+func commentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTodoNotFound), errors.Is(err, service.ErrCommentNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrNotCommentAuthor):
+		return fiber.StatusForbidden
+	}
+	return fiber.StatusInternalServerError
+}
@@ -1,33 +1,161 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testbox/internal/models"
+	"testbox/internal/recurrence"
+	"testbox/internal/repository"
 	"testbox/internal/service"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type TodoHandler struct {
 	service service.TodoService
+	undo    service.UndoService
 }
 
-func NewTodoHandler(service service.TodoService) *TodoHandler {
-	return &TodoHandler{service: service}
+func NewTodoHandler(service service.TodoService, undo service.UndoService) *TodoHandler {
+	return &TodoHandler{service: service, undo: undo}
 }
 
 type CreateTodoRequest struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title      string     `json:"title"`
+	Content    string     `json:"content"`
+	Status     string     `json:"status"` // 생략하면 워크플로 초기 상태
+	Priority   int        `json:"priority"`
+	StartDate  *time.Time `json:"start_date"`
+	DueDate    *time.Time `json:"due_date"`
+	ReminderAt *time.Time `json:"reminder_at"`
+	Recurrence string     `json:"recurrence"`
+	ListID     *string    `json:"list_id"`
+	Tags       []string   `json:"tags"`
 }
 
+// UpdateTodoRequest 에서 생략한 일정 필드(priority, start_date, due_date, reminder_at, recurrence)는
+// 기존 값을 유지합니다. 값을 지우려면 null 또는 빈 값을 명시적으로 보냅니다.
 type UpdateTodoRequest struct {
-	Title     string `json:"title"`
-	Content   string `json:"content"`
-	Completed bool   `json:"completed"`
+	Title      string     `json:"title"`
+	Content    string     `json:"content"`
+	Completed  bool       `json:"completed"`
+	Status     string     `json:"status"` // 지정하면 completed 대신 사용
+	Priority   int        `json:"priority"`
+	StartDate  *time.Time `json:"start_date"`
+	DueDate    *time.Time `json:"due_date"`
+	ReminderAt *time.Time `json:"reminder_at"`
+	Recurrence string     `json:"recurrence"`
+	Tags       []string   `json:"tags"` // 생략하면 기존 태그 유지
+}
+
+// toInput 은 요청을 검증하고 서비스 입력값으로 변환합니다 (실패 시 오류 메시지 반환)
+func (req *CreateTodoRequest) toInput() (service.TodoInput, string) {
+	if req.Title == "" {
+		return service.TodoInput{}, "Title is required"
+	}
+
+	if msg := validateSchedule(req.Priority, req.StartDate, req.DueDate); msg != "" {
+		return service.TodoInput{}, msg
+	}
+
+	rule, msg := normalizeRecurrence(req.Recurrence)
+	if msg != "" {
+		return service.TodoInput{}, msg
+	}
+
+	return service.TodoInput{
+		Title:      req.Title,
+		Content:    req.Content,
+		Status:     req.Status,
+		Priority:   req.Priority,
+		StartDate:  req.StartDate,
+		DueDate:    req.DueDate,
+		ReminderAt: req.ReminderAt,
+		Recurrence: rule,
+		ListID:     req.ListID,
+		Tags:       req.Tags,
+	}, ""
+}
+
+// toInput 은 요청을 검증하고 서비스 입력값으로 변환합니다 (실패 시 오류 메시지 반환)
+func (req *UpdateTodoRequest) toInput() (service.TodoInput, string) {
+	if req.Title == "" {
+		return service.TodoInput{}, "Title is required"
+	}
+
+	if msg := validateSchedule(req.Priority, req.StartDate, req.DueDate); msg != "" {
+		return service.TodoInput{}, msg
+	}
+
+	rule, msg := normalizeRecurrence(req.Recurrence)
+	if msg != "" {
+		return service.TodoInput{}, msg
+	}
+
+	return service.TodoInput{
+		Title:      req.Title,
+		Content:    req.Content,
+		Completed:  req.Completed,
+		Status:     req.Status,
+		Priority:   req.Priority,
+		StartDate:  req.StartDate,
+		DueDate:    req.DueDate,
+		ReminderAt: req.ReminderAt,
+		Recurrence: rule,
+		Tags:       req.Tags,
+	}, ""
+}
+
+// omittedScheduleFields 는 JSON 요청 본문에 없는 일정 필드를 표시합니다.
+// JSON 객체가 아닌 본문(폼 등)은 모든 필드를 보낸 것으로 봅니다.
+func omittedScheduleFields(body []byte) service.KeepFields {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return service.KeepFields{}
+	}
+
+	omitted := func(name string) bool {
+		_, ok := fields[name]
+		return !ok
+	}
+	return service.KeepFields{
+		Priority:   omitted("priority"),
+		StartDate:  omitted("start_date"),
+		DueDate:    omitted("due_date"),
+		ReminderAt: omitted("reminder_at"),
+		Recurrence: omitted("recurrence"),
+	}
+}
+
+// normalizeRecurrence 는 반복 규칙을 검증하고 정규화된 RRULE 문자열을 반환합니다
+func normalizeRecurrence(raw string) (string, string) {
+	if raw == "" {
+		return "", ""
+	}
+	rule, err := recurrence.Parse(raw)
+	if err != nil {
+		return "", "Invalid recurrence rule: " + err.Error()
+	}
+	return rule.String(), ""
+}
+
+// validateSchedule 는 우선순위와 일정 필드의 유효성을 검사하고 오류 메시지를 반환합니다
+func validateSchedule(priority int, startDate, dueDate *time.Time) string {
+	if !models.IsValidPriority(priority) {
+		return "Priority must be between 0 and 4"
+	}
+	if startDate != nil && dueDate != nil && dueDate.Before(*startDate) {
+		return "Due date must not be before start date"
+	}
+	return ""
 }
 
 // CreateTodo 는 새로운 Todo를 생성합니다
 // @Summary 새로운 Todo 생성
-// @Description 제목과 내용을 받아 새로운 Todo를 생성합니다
+// @Description 제목, 내용, 우선순위, 시작일/마감일을 받아 새로운 Todo를 생성합니다
 // @Tags todos
 // @Accept json
 // @Produce json
@@ -41,36 +169,118 @@ func (h *TodoHandler) CreateTodo(c *fiber.Ctx) error {
 		})
 	}
 
-	if req.Title == "" {
+	input, msg := req.toInput()
+	if msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+	input.Actor = currentUserID(c)
+
+	todo, err := h.service.CreateTodo(input)
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(todo)
+}
+
+// QuickAddRequest 는 한 줄로 Todo를 추가하는 요청입니다
+type QuickAddRequest struct {
+	Text string `json:"text"` // 예: "Pay rent every month on the 1st #finance !high"
+}
+
+// parseQuickAdd 는 빠른 추가 요청 본문과 날짜 해석에 사용할 현재 시각(tz 쿼리 기준)을 읽습니다
+func parseQuickAdd(c *fiber.Ctx) (string, time.Time, string) {
+	var req QuickAddRequest
+	if err := c.BodyParser(&req); err != nil {
+		return "", time.Time{}, "Invalid request body"
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		return "", time.Time{}, "Text is required"
+	}
+
+	now := time.Now()
+	if tz := c.Query("tz"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return "", time.Time{}, "Invalid time zone"
+		}
+		now = now.In(loc)
+	}
+	return req.Text, now, ""
+}
+
+// PreviewQuickAdd 는 한 줄 입력을 해석한 결과를 저장하지 않고 반환합니다
+// @Summary 빠른 추가 미리보기
+// @Description "Pay rent every month on the 1st #finance !high" 같은 입력에서 제목, 마감일, 반복 규칙, 태그(#), 우선순위(!), 목록(@)을 추출합니다. 저장할 수 없는 입력이면 warnings 에 이유를 담습니다
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Param tz query string false "날짜 해석에 사용할 IANA 시간대 (기본: 서버 시간대)"
+// @Success 200 {object} service.QuickAddPreview
+// @Router /api/todos/quick-add/preview [post]
+func (h *TodoHandler) PreviewQuickAdd(c *fiber.Ctx) error {
+	text, now, msg := parseQuickAdd(c)
+	if msg != "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Title is required",
+			"error": msg,
 		})
 	}
 
-	todo, err := h.service.CreateTodo(req.Title, req.Content)
+	preview, err := h.service.PreviewQuickAdd(text, now)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
+	return c.JSON(preview)
+}
+
+// QuickAdd 는 한 줄 입력을 해석해 Todo를 생성합니다
+// @Summary 빠른 추가
+// @Description 미리보기와 같은 규칙으로 입력을 해석해 Todo를 생성합니다 (제목이 없으면 400, 목록이 없으면 404)
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Param tz query string false "날짜 해석에 사용할 IANA 시간대 (기본: 서버 시간대)"
+// @Success 201 {object} models.Todo
+// @Router /api/todos/quick-add [post]
+func (h *TodoHandler) QuickAdd(c *fiber.Ctx) error {
+	text, now, msg := parseQuickAdd(c)
+	if msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	todo, err := h.service.QuickAdd(text, now, currentUserID(c))
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(todo)
 }
 
 // GetTodo 는 ID로 특정 Todo를 조회합니다
 // @Summary ID로 Todo 조회
-// @Description 주어진 ID에 해당하는 Todo를 조회합니다 (캐시 우선 조회)
+// @Description 주어진 ID에 해당하는 Todo를 선행 Todo(blockers), 후행 Todo(dependents)와 함께 조회합니다 (캐시 우선 조회)
 // @Tags todos
 // @Produce json
 // @Param id path string true "Todo ID"
-// @Success 200 {object} models.Todo
+// @Success 200 {object} models.TodoDetail
 // @Router /api/todos/{id} [get]
 func (h *TodoHandler) GetTodo(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	todo, err := h.service.GetTodo(id)
+	todo, err := h.service.GetTodoDetail(id)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
@@ -78,27 +288,57 @@ func (h *TodoHandler) GetTodo(c *fiber.Ctx) error {
 	return c.JSON(todo)
 }
 
-// GetAllTodos 는 모든 Todo 목록을 조회합니다
+// GetAllTodos 는 Todo 목록을 조회합니다
 // @Summary 전체 Todo 목록 조회
-// @Description 등록된 모든 Todo를 생성일 역순으로 조회합니다
+// @Description 완료 상태, 검색어, 날짜 범위, 마감일(overdue/today/week), 우선순위로 필터링하고 커서 기반으로 페이지를 나눕니다 (기본: 사용자 지정 순서)
 // @Tags todos
 // @Produce json
-// @Success 200 {array} models.Todo
+// @Param completed query bool false "완료 상태"
+// @Param status query string false "워크플로 상태 (backlog, todo, in_progress, blocked, done 등)"
+// @Param archived query string false "보관된 Todo 포함 여부 (include, only; 기본은 제외)"
+// @Param parent_id query string false "상위 Todo ID (직계 하위만 조회)"
+// @Param root_only query bool false "최상위 Todo만 조회"
+// @Param list_id query string false "목록 ID (none 이면 목록 없는 Todo)"
+// @Param tags query string false "쉼표로 구분한 태그 이름 (모두 포함하는 Todo)"
+// @Param q query string false "제목/내용 검색어"
+// @Param created_from query string false "생성일 시작 (RFC3339 또는 YYYY-MM-DD)"
+// @Param created_to query string false "생성일 끝 (미포함)"
+// @Param due_from query string false "마감일 시작 (RFC3339 또는 YYYY-MM-DD)"
+// @Param due_to query string false "마감일 끝 (미포함)"
+// @Param due query string false "마감일 필터 (overdue, today, week)"
+// @Param priority query int false "우선순위 (0-4)"
+// @Param sort query string false "정렬 필드 (position, created_at, updated_at, due_date, start_date, priority, title, completed_at, archived_at)"
+// @Param order query string false "정렬 방향 (asc, desc)"
+// @Param cursor query string false "이전 응답의 next_cursor"
+// @Param limit query int false "페이지 크기 (기본 50, 최대 200)"
+// @Success 200 {object} repository.TodoPage
 // @Router /api/todos [get]
 func (h *TodoHandler) GetAllTodos(c *fiber.Ctx) error {
-	todos, err := h.service.GetAllTodos()
+	query, msg := parseTodoQuery(c)
+	if msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	page, err := h.service.GetAllTodos(query)
 	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid cursor",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
-	return c.JSON(todos)
+	return c.JSON(page)
 }
 
 // UpdateTodo 는 기존 Todo를 수정합니다
 // @Summary Todo 수정
-// @Description 제목, 내용, 완료 상태를 수정하고 캐시를 업데이트합니다 (Write-Through)
+// @Description 제목, 내용, 상태(또는 완료 여부), 우선순위, 일정, 반복 규칙을 수정하고 캐시를 업데이트합니다 (Write-Through). 생략한 일정 필드는 기존 값을 유지합니다. 상태 전환은 워크플로 규칙을 따르며, 반복 Todo를 완료하면 다음 회차가 생성됩니다. X-Undo-Token 헤더의 토큰으로 되돌릴 수 있습니다
 // @Tags todos
 // @Accept json
 // @Produce json
@@ -115,25 +355,30 @@ func (h *TodoHandler) UpdateTodo(c *fiber.Ctx) error {
 		})
 	}
 
-	if req.Title == "" {
+	input, msg := req.toInput()
+	if msg != "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Title is required",
+			"error": msg,
 		})
 	}
+	input.Keep = omittedScheduleFields(c.Body())
+	input.Actor = currentUserID(c)
 
-	todo, err := h.service.UpdateTodo(id, req.Title, req.Content, req.Completed)
+	undo := h.undo.Capture(service.UndoUpdate, id)
+	todo, err := h.service.UpdateTodo(id, input)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
+	setUndoToken(c, h.undo, undo)
 	return c.JSON(todo)
 }
 
 // DeleteTodo 는 Todo를 삭제합니다
 // @Summary Todo 삭제
-// @Description Todo를 삭제하고 캐시에서도 제거합니다
+// @Description Todo와 모든 하위 Todo를 삭제하고 캐시에서도 제거합니다. X-Undo-Token 헤더의 토큰으로 되돌릴 수 있습니다
 // @Tags todos
 // @Param id path string true "Todo ID"
 // @Success 204
@@ -141,11 +386,627 @@ func (h *TodoHandler) UpdateTodo(c *fiber.Ctx) error {
 func (h *TodoHandler) DeleteTodo(c *fiber.Ctx) error {
 	id := c.Params("id")
 
+	undo := h.undo.Capture(service.UndoDelete, id)
 	if err := h.service.DeleteTodo(id); err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	setUndoToken(c, h.undo, undo)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// CreateSubtask 는 특정 Todo 아래에 하위 Todo를 생성합니다
+// @Summary 하위 Todo 생성
+// @Description 상위 Todo 아래에 하위 Todo를 생성하고 상위의 완료 상태를 재계산합니다
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Param id path string true "상위 Todo ID"
+// @Success 201 {object} models.Todo
+// @Router /api/todos/{id}/subtasks [post]
+func (h *TodoHandler) CreateSubtask(c *fiber.Ctx) error {
+	parentID := c.Params("id")
+
+	var req CreateTodoRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	input, msg := req.toInput()
+	if msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+	input.Actor = currentUserID(c)
+
+	// 하위 Todo 의 목록은 상위 Todo 를 따릅니다
+	input.ListID = nil
+
+	todo, err := h.service.CreateSubtask(parentID, input)
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(todo)
+}
+
+// GetSubtasks 는 직계 하위 Todo 목록을 조회합니다
+// @Summary 하위 Todo 목록 조회
+// @Description 특정 Todo의 직계 하위 Todo를 생성일 순으로 조회합니다
+// @Tags todos
+// @Produce json
+// @Param id path string true "상위 Todo ID"
+// @Success 200 {array} models.Todo
+// @Router /api/todos/{id}/subtasks [get]
+func (h *TodoHandler) GetSubtasks(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	todos, err := h.service.GetSubtasks(id)
+	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
+	return c.JSON(todos)
+}
+
+// GetTodoTree 는 Todo와 모든 하위 Todo를 트리 형태로 조회합니다
+// @Summary Todo 트리 조회
+// @Description Todo와 모든 하위 Todo를 children 필드로 중첩하여 반환합니다
+// @Tags todos
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {object} models.TodoNode
+// @Router /api/todos/{id}/tree [get]
+func (h *TodoHandler) GetTodoTree(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	tree, err := h.service.GetTodoTree(id)
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(tree)
+}
+
+// MoveTodoRequest 는 Todo 이동 요청입니다 (list_id 가 null 이면 목록에서 제외)
+type MoveTodoRequest struct {
+	ListID *string `json:"list_id"`
+}
+
+// MoveTodo 는 Todo를 다른 목록으로 이동합니다
+// @Summary Todo 목록 이동
+// @Description Todo와 모든 하위 Todo를 지정한 목록으로 이동합니다 (list_id 가 null 이면 목록 없음)
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {object} models.Todo
+// @Router /api/todos/{id}/move [post]
+func (h *TodoHandler) MoveTodo(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req MoveTodoRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	undo := h.undo.Capture(service.UndoMove, id)
+	todo, err := h.service.MoveTodo(id, req.ListID)
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	setUndoToken(c, h.undo, undo)
+	return c.JSON(todo)
+}
+
+// ChangeStatusRequest 는 Todo 상태 전환 요청입니다
+type ChangeStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// ChangeStatus 는 Todo의 워크플로 상태를 전환합니다
+// @Summary Todo 상태 전환
+// @Description 워크플로에서 허용된 상태로 Todo를 전환하고 status_changed 이벤트를 발행합니다 (done 이면 완료 처리)
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {object} models.Todo
+// @Router /api/todos/{id}/status [post]
+func (h *TodoHandler) ChangeStatus(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req ChangeStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.Status == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Status is required",
+		})
+	}
+
+	undo := h.undo.Capture(service.UndoUpdate, id)
+	todo, err := h.service.ChangeStatus(id, req.Status, currentUserID(c))
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	setUndoToken(c, h.undo, undo)
+	return c.JSON(todo)
+}
+
+// GetBoard 는 Todo를 상태별로 묶은 보드를 조회합니다
+// @Summary 상태별 보드 조회
+// @Description 필터에 맞는 Todo를 워크플로 상태 순서의 열로 묶어 반환합니다 (각 열은 사용자 지정 순서, 페이지 없음). GET /api/todos 와 같은 필터를 사용합니다
+// @Tags todos
+// @Produce json
+// @Param list_id query string false "목록 ID (none 이면 목록 없는 Todo)"
+// @Param tags query string false "쉼표로 구분한 태그 이름"
+// @Param root_only query bool false "최상위 Todo만 조회"
+// @Success 200 {array} models.BoardColumn
+// @Router /api/todos/board [get]
+func (h *TodoHandler) GetBoard(c *fiber.Ctx) error {
+	query, msg := parseTodoQuery(c)
+	if msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	columns, err := h.service.GetBoard(query)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(columns)
+}
+
+// ArchiveTodo 는 Todo를 보관합니다
+// @Summary Todo 보관
+// @Description Todo와 모든 하위 Todo를 보관합니다. 보관된 Todo는 기본 목록 조회에서 제외됩니다
+// @Tags todos
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {object} models.Todo
+// @Router /api/todos/{id}/archive [post]
+func (h *TodoHandler) ArchiveTodo(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	todo, err := h.service.ArchiveTodo(id)
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(todo)
+}
+
+// UnarchiveTodo 는 Todo를 보관 상태에서 꺼냅니다
+// @Summary Todo 보관 해제
+// @Description Todo와 모든 하위 Todo를 보관 상태에서 꺼냅니다
+// @Tags todos
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {object} models.Todo
+// @Router /api/todos/{id}/unarchive [post]
+func (h *TodoHandler) UnarchiveTodo(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	todo, err := h.service.UnarchiveTodo(id)
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(todo)
+}
+
+// GetArchivedTodos 는 보관된 Todo 목록을 조회합니다
+// @Summary 보관된 Todo 조회
+// @Description 보관된 Todo를 커서 기반으로 페이지를 나눠 조회합니다 (기본: 최근 보관 순). GET /api/todos 와 같은 필터를 사용합니다
+// @Tags todos
+// @Produce json
+// @Param list_id query string false "목록 ID (none 이면 목록 없는 Todo)"
+// @Param tags query string false "쉼표로 구분한 태그 이름"
+// @Param q query string false "제목/내용 검색어"
+// @Param sort query string false "정렬 필드 (기본 archived_at)"
+// @Param order query string false "정렬 방향 (asc, desc)"
+// @Param cursor query string false "이전 응답의 next_cursor"
+// @Param limit query int false "페이지 크기 (기본 50, 최대 200)"
+// @Success 200 {object} repository.TodoPage
+// @Router /api/todos/archive [get]
+func (h *TodoHandler) GetArchivedTodos(c *fiber.Ctx) error {
+	query, msg := parseTodoQuery(c)
+	if msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	// 보관함은 최근 보관한 순서가 기본입니다
+	if c.Query("sort") == "" {
+		query.SortBy = "archived_at"
+		query.Order = c.Query("order", "desc")
+	}
+
+	page, err := h.service.GetArchivedTodos(query)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid cursor",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(page)
+}
+
+// AddDependencyRequest 는 선행 Todo 지정 요청입니다
+type AddDependencyRequest struct {
+	BlockerID string `json:"blocker_id"`
+}
+
+// AddDependency 는 Todo에 선행 Todo를 추가합니다
+// @Summary 선행 Todo 추가
+// @Description blocker_id Todo가 완료되기 전에는 이 Todo를 완료할 수 없도록 의존 관계를 추가합니다 (순환이 생기면 409)
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 201 {object} models.TodoDetail
+// @Router /api/todos/{id}/dependencies [post]
+func (h *TodoHandler) AddDependency(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req AddDependencyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.BlockerID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "blocker_id is required",
+		})
+	}
+
+	detail, err := h.service.AddDependency(id, req.BlockerID)
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(detail)
+}
+
+// RemoveDependency 는 Todo의 선행 Todo를 제거합니다
+// @Summary 선행 Todo 제거
+// @Description Todo와 선행 Todo 사이의 의존 관계를 삭제합니다
+// @Tags todos
+// @Param id path string true "Todo ID"
+// @Param blockerId path string true "선행 Todo ID"
+// @Success 204
+// @Router /api/todos/{id}/dependencies/{blockerId} [delete]
+func (h *TodoHandler) RemoveDependency(c *fiber.Ctx) error {
+	id := c.Params("id")
+	blockerID := c.Params("blockerId")
+
+	if err := h.service.RemoveDependency(id, blockerID); err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// todoErrorStatus 는 서비스 오류를 HTTP 상태 코드로 변환합니다 (알 수 없는 오류는 fallback)
+func todoErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, service.ErrTodoNotFound), errors.Is(err, service.ErrListNotFound),
+		errors.Is(err, service.ErrDependencyNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrInvalidStatus), errors.Is(err, service.ErrInvalidBulkAction),
+		errors.Is(err, service.ErrQuickAddNoTitle), errors.Is(err, service.ErrInvalidSchedule),
+		errors.Is(err, service.ErrDuplicateTodoID):
+		return fiber.StatusBadRequest
+	case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrOpenBlockers),
+		errors.Is(err, service.ErrDependencyCycle), errors.Is(err, service.ErrSubtaskMove):
+		return fiber.StatusConflict
+	}
+	return fallback
+}
+
+// ReorderTodoRequest 는 Todo 하나의 위치 변경 요청입니다 (before_id / after_id 중 하나만 지정)
+type ReorderTodoRequest struct {
+	BeforeID string `json:"before_id"`
+	AfterID  string `json:"after_id"`
+}
+
+// BulkReorderRequest 는 여러 Todo 의 순서를 한 번에 지정하는 요청입니다
+type BulkReorderRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// ReorderTodo 는 Todo를 다른 Todo 앞이나 뒤로 옮깁니다
+// @Summary Todo 순서 변경
+// @Description before_id 바로 앞 또는 after_id 바로 뒤로 Todo를 옮깁니다 (이동한 Todo만 갱신)
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {object} models.Todo
+// @Router /api/todos/{id}/reorder [post]
+func (h *TodoHandler) ReorderTodo(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req ReorderTodoRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if (req.BeforeID == "") == (req.AfterID == "") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Exactly one of before_id or after_id is required",
+		})
+	}
+
+	undo := h.undo.Capture(service.UndoReorder, id)
+	todo, err := h.service.ReorderTodo(id, req.BeforeID, req.AfterID)
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	setUndoToken(c, h.undo, undo)
+	return c.JSON(todo)
+}
+
+// ReorderTodos 는 여러 Todo 의 순서를 한 번에 변경합니다
+// @Summary Todo 일괄 순서 변경
+// @Description 주어진 Todo 들이 ids 순서대로 나열되도록 위치를 바꿉니다 (다른 Todo 와의 상대 순서는 유지)
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Todo
+// @Router /api/todos/reorder [post]
+func (h *TodoHandler) ReorderTodos(c *fiber.Ctx) error {
+	var req BulkReorderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if len(req.IDs) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ids is required",
+		})
+	}
+
+	undo := h.undo.Capture(service.UndoReorder, req.IDs...)
+	todos, err := h.service.ReorderTodos(req.IDs)
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	setUndoToken(c, h.undo, undo)
+	return c.JSON(todos)
+}
+
+// maxBulkSize 는 일괄 작업 한 번에 처리할 수 있는 최대 Todo 수입니다
+const maxBulkSize = 500
+
+// BulkTodoRequest 는 여러 Todo에 같은 작업을 적용하는 요청입니다
+type BulkTodoRequest struct {
+	IDs    []string `json:"ids"`
+	Action string   `json:"action"`  // complete, uncomplete, delete, move, add_tag
+	ListID *string  `json:"list_id"` // move 대상 목록 (null 이면 목록 없음)
+	Tag    string   `json:"tag"`     // add_tag 로 붙일 태그
+}
+
+// BulkTodos 는 여러 Todo를 한 번에 완료/완료 취소/삭제/이동하거나 태그를 붙입니다
+// @Summary Todo 일괄 작업
+// @Description ids 의 Todo 들에 action 을 하나의 트랜잭션으로 적용하고 항목별 결과를 반환합니다 (검증에 실패한 항목만 제외). X-Undo-Token 헤더의 토큰으로 되돌릴 수 있습니다
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Success 200 {object} service.BulkResult
+// @Router /api/todos/bulk [post]
+func (h *TodoHandler) BulkTodos(c *fiber.Ctx) error {
+	var req BulkTodoRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if len(req.IDs) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ids is required",
+		})
+	}
+	if len(req.IDs) > maxBulkSize {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Too many ids (max " + strconv.Itoa(maxBulkSize) + ")",
+		})
+	}
+
+	op := service.UndoUpdate
+	switch req.Action {
+	case service.BulkDelete:
+		op = service.UndoDelete
+	case service.BulkMove:
+		op = service.UndoMove
+	}
+	undo := h.undo.Capture(op, req.IDs...)
+
+	result, err := h.service.BulkUpdate(service.BulkInput{
+		Action: req.Action,
+		IDs:    req.IDs,
+		ListID: req.ListID,
+		Tag:    req.Tag,
+		Actor:  currentUserID(c),
+	})
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	setUndoToken(c, h.undo, undo.Only(result.Applied()))
+	return c.JSON(result)
+}
+
+// parseTodoQuery 는 목록 조회용 쿼리 파라미터를 해석합니다
+func parseTodoQuery(c *fiber.Ctx) (repository.TodoQuery, string) {
+	query := repository.TodoQuery{
+		Due:    c.Query("due"),
+		Search: c.Query("q"),
+		Status: c.Query("status"),
+		Tags:   models.NormalizeTagNames(parseTagQuery(c.Query("tags"))),
+		SortBy: c.Query("sort", "position"),
+		Cursor: c.Query("cursor"),
+	}
+
+	// 수동 순서는 오름차순, 나머지 필드는 최신순이 기본입니다
+	defaultOrder := "desc"
+	if query.SortBy == "position" {
+		defaultOrder = "asc"
+	}
+	query.Order = c.Query("order", defaultOrder)
+
+	switch query.Due {
+	case "", repository.DueOverdue, repository.DueToday, repository.DueThisWeek:
+	default:
+		return query, "due must be one of overdue, today, week"
+	}
+
+	if !repository.IsValidTodoSort(query.SortBy) {
+		return query, "Unsupported sort field"
+	}
+
+	if query.Order != "asc" && query.Order != "desc" {
+		return query, "order must be asc or desc"
+	}
+
+	if raw := c.Query("priority"); raw != "" {
+		priority, err := strconv.Atoi(raw)
+		if err != nil || !models.IsValidPriority(priority) {
+			return query, "Priority must be between 0 and 4"
+		}
+		query.Priority = &priority
+	}
+
+	if raw := c.Query("parent_id"); raw != "" {
+		query.ParentID = &raw
+	}
+
+	// 보관된 Todo 는 archived=include 또는 archived=only 일 때만 조회됩니다
+	query.Archived = c.Query("archived")
+	switch query.Archived {
+	case repository.ArchivedExclude, repository.ArchivedInclude, repository.ArchivedOnly:
+	default:
+		return query, "archived must be include or only"
+	}
+
+	// list_id=none 은 목록에 속하지 않은 Todo 를 의미합니다
+	if raw := c.Query("list_id"); raw != "" {
+		listID := raw
+		if raw == "none" {
+			listID = ""
+		}
+		query.ListID = &listID
+	}
+
+	if raw := c.Query("root_only"); raw != "" {
+		rootOnly, err := strconv.ParseBool(raw)
+		if err != nil {
+			return query, "root_only must be true or false"
+		}
+		query.RootOnly = rootOnly
+	}
+
+	if raw := c.Query("completed"); raw != "" {
+		completed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return query, "completed must be true or false"
+		}
+		query.Completed = &completed
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > repository.MaxTodoPageSize {
+			return query, "limit must be between 1 and 200"
+		}
+		query.Limit = limit
+	}
+
+	dateParams := []struct {
+		name   string
+		target **time.Time
+	}{
+		{"created_from", &query.CreatedFrom},
+		{"created_to", &query.CreatedTo},
+		{"due_from", &query.DueFrom},
+		{"due_to", &query.DueTo},
+	}
+	for _, p := range dateParams {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := parseQueryTime(raw)
+		if err != nil {
+			return query, p.name + " must be RFC3339 or YYYY-MM-DD"
+		}
+		*p.target = &t
+	}
+
+	return query, ""
+}
+
+// parseQueryTime 는 RFC3339 또는 YYYY-MM-DD 형식의 날짜를 해석합니다
+func parseQueryTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", raw, time.Local)
+}
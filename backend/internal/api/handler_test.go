@@ -0,0 +1,67 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"testbox/internal/service"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestOmittedScheduleFields(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want service.KeepFields
+	}{
+		{
+			name: "web UI edit",
+			body: `{"title":"Pay rent","content":"","completed":false}`,
+			want: service.KeepFields{Priority: true, StartDate: true, DueDate: true, ReminderAt: true, Recurrence: true},
+		},
+		{
+			name: "explicit null clears the due date",
+			body: `{"title":"Pay rent","due_date":null,"priority":0}`,
+			want: service.KeepFields{StartDate: true, ReminderAt: true, Recurrence: true},
+		},
+		{
+			name: "every field",
+			body: `{"title":"Pay rent","priority":3,"start_date":null,"due_date":null,"reminder_at":null,"recurrence":""}`,
+			want: service.KeepFields{},
+		},
+		{
+			name: "not JSON",
+			body: `title=Pay+rent`,
+			want: service.KeepFields{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := omittedScheduleFields([]byte(tt.body)); got != tt.want {
+				t.Errorf("omittedScheduleFields(%s) = %+v, want %+v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTodoErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not found", service.ErrTodoNotFound, fiber.StatusNotFound},
+		{"wrapped anchor not found", fmt.Errorf("기준 %w", service.ErrTodoNotFound), fiber.StatusNotFound},
+		{"duplicate id", fmt.Errorf("%w: a", service.ErrDuplicateTodoID), fiber.StatusBadRequest},
+		{"subtask move", service.ErrSubtaskMove, fiber.StatusConflict},
+		{"database failure", fmt.Errorf("Todo 순서 변경 실패: %w", errors.New("connection reset")), fiber.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		if got := todoErrorStatus(tt.err, fiber.StatusInternalServerError); got != tt.want {
+			t.Errorf("%s: todoErrorStatus(%v) = %d, want %d", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"errors"
+	"testbox/internal/repository"
+	"testbox/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ListHandler struct {
+	service     service.ListService
+	todoService service.TodoService
+}
+
+func NewListHandler(service service.ListService, todoService service.TodoService) *ListHandler {
+	return &ListHandler{service: service, todoService: todoService}
+}
+
+type ListRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+}
+
+// CreateList creates a new list
+// @Summary Create a new list
+// @Description Creates a list (project) that groups todos
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.List
+// @Router /api/lists [post]
+func (h *ListHandler) CreateList(c *fiber.Ctx) error {
+	var req ListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Name is required",
+		})
+	}
+
+	list, err := h.service.CreateList(req.Name, req.Description, req.Color)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(list)
+}
+
+// GetList retrieves a list with its todo counts
+// @Summary Get list by ID
+// @Description Retrieves a list with the number of open and done todos
+// @Tags lists
+// @Produce json
+// @Param id path string true "List ID"
+// @Success 200 {object} models.ListSummary
+// @Router /api/lists/{id} [get]
+func (h *ListHandler) GetList(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	list, err := h.service.GetList(id)
+	if err != nil {
+		return c.Status(listErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(list)
+}
+
+// GetAllLists retrieves all lists with their todo counts
+// @Summary Get all lists
+// @Description Retrieves all lists with open/done todo counts, oldest first
+// @Tags lists
+// @Produce json
+// @Success 200 {array} models.ListSummary
+// @Router /api/lists [get]
+func (h *ListHandler) GetAllLists(c *fiber.Ctx) error {
+	lists, err := h.service.GetAllLists()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(lists)
+}
+
+// UpdateList updates an existing list
+// @Summary Update list
+// @Description Updates name, description and color of a list
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID"
+// @Success 200 {object} models.List
+// @Router /api/lists/{id} [put]
+func (h *ListHandler) UpdateList(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req ListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Name is required",
+		})
+	}
+
+	list, err := h.service.UpdateList(id, req.Name, req.Description, req.Color)
+	if err != nil {
+		return c.Status(listErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(list)
+}
+
+// DeleteList deletes a list
+// @Summary Delete list
+// @Description Deletes a list; its todos are kept and moved out of the list
+// @Tags lists
+// @Param id path string true "List ID"
+// @Success 204
+// @Router /api/lists/{id} [delete]
+func (h *ListHandler) DeleteList(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.service.DeleteList(id); err != nil {
+		return c.Status(listErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetListTodos retrieves the todos of a list
+// @Summary Get todos in a list
+// @Description Retrieves a page of todos in the list; accepts the same query parameters as GET /api/todos
+// @Tags lists
+// @Produce json
+// @Param id path string true "List ID"
+// @Success 200 {object} repository.TodoPage
+// @Router /api/lists/{id}/todos [get]
+func (h *ListHandler) GetListTodos(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	query, msg := parseTodoQuery(c)
+	if msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	page, err := h.service.GetListTodos(id, query)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid cursor",
+			})
+		}
+		return c.Status(listErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(page)
+}
+
+// CreateListTodo creates a todo inside a list
+// @Summary Create a todo in a list
+// @Description Creates a new todo that belongs to the list
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID"
+// @Success 201 {object} models.Todo
+// @Router /api/lists/{id}/todos [post]
+func (h *ListHandler) CreateListTodo(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req CreateTodoRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	input, msg := req.toInput()
+	if msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+	input.ListID = &id
+	input.Actor = currentUserID(c)
+
+	todo, err := h.todoService.CreateTodo(input)
+	if err != nil {
+		return c.Status(todoErrorStatus(err, fiber.StatusInternalServerError)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(todo)
+}
+
+// listErrorStatus maps a missing list to 404 and everything else to 500
+func listErrorStatus(err error) int {
+	if errors.Is(err, service.ErrListNotFound) {
+		return fiber.StatusNotFound
+	}
+	return fiber.StatusInternalServerError
+}
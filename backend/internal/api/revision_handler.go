@@ -0,0 +1,233 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"testbox/internal/models"
+	"testbox/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RevisionHandler struct {
+	service     service.RevisionService
+	todoService service.TodoService
+	blogService service.BlogService
+}
+
+func NewRevisionHandler(service service.RevisionService, todoService service.TodoService, blogService service.BlogService) *RevisionHandler {
+	return &RevisionHandler{service: service, todoService: todoService, blogService: blogService}
+}
+
+// GetTodoRevisions lists the change history of a todo
+// @Summary List todo revisions
+// @Description Lists the todo's revisions newest first, each with its author, changed fields and snapshot
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {array} models.RevisionView
+// @Router /api/todos/{id}/revisions [get]
+func (h *RevisionHandler) GetTodoRevisions(c *fiber.Ctx) error {
+	return h.getRevisions(c, models.RevisionOwnerTodo)
+}
+
+// GetTodoRevision retrieves a single todo revision
+// @Summary Get todo revision
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param number path int true "Revision number"
+// @Success 200 {object} models.RevisionView
+// @Router /api/todos/{id}/revisions/{number} [get]
+func (h *RevisionHandler) GetTodoRevision(c *fiber.Ctx) error {
+	return h.getRevision(c, models.RevisionOwnerTodo)
+}
+
+// DiffTodoRevisions compares two todo revisions
+// @Summary Diff todo revisions
+// @Description Lists the fields that differ between revision "from" and revision "to"
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param from query int true "Older revision number"
+// @Param to query int true "Newer revision number"
+// @Success 200 {object} models.RevisionDiff
+// @Router /api/todos/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffTodoRevisions(c *fiber.Ctx) error {
+	return h.diffRevisions(c, models.RevisionOwnerTodo)
+}
+
+// RevertTodo reverts a todo to a revision
+// @Summary Revert todo
+// @Description Restores the todo's fields from the revision, following the workflow's transition rules. The revert is recorded as a new revision.
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param number path int true "Revision number"
+// @Success 200 {object} models.Todo
+// @Router /api/todos/{id}/revisions/{number}/revert [post]
+func (h *RevisionHandler) RevertTodo(c *fiber.Ctx) error {
+	number, err := strconv.Atoi(c.Params("number"))
+	if err != nil || number < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid revision number",
+		})
+	}
+
+	todo, err := h.todoService.RevertTodo(c.Params("id"), number, currentUserID(c))
+	if err != nil {
+		return c.Status(revisionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(todo)
+}
+
+// GetBlogRevisions lists the draft history of a blog post
+// @Summary List blog post revisions
+// @Description Lists the post's revisions newest first, each with its author, changed fields and snapshot
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Success 200 {array} models.RevisionView
+// @Router /api/blogs/{id}/revisions [get]
+func (h *RevisionHandler) GetBlogRevisions(c *fiber.Ctx) error {
+	return h.getRevisions(c, models.RevisionOwnerBlog)
+}
+
+// GetBlogRevision retrieves a single blog post revision
+// @Summary Get blog post revision
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Param number path int true "Revision number"
+// @Success 200 {object} models.RevisionView
+// @Router /api/blogs/{id}/revisions/{number} [get]
+func (h *RevisionHandler) GetBlogRevision(c *fiber.Ctx) error {
+	return h.getRevision(c, models.RevisionOwnerBlog)
+}
+
+// DiffBlogRevisions compares two blog post revisions
+// @Summary Diff blog post revisions
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Param from query int true "Older revision number"
+// @Param to query int true "Newer revision number"
+// @Success 200 {object} models.RevisionDiff
+// @Router /api/blogs/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffBlogRevisions(c *fiber.Ctx) error {
+	return h.diffRevisions(c, models.RevisionOwnerBlog)
+}
+
+// RevertBlog reverts a blog post to a revision
+// @Summary Revert blog post
+// @Description Restores the post's title, content and tags from the revision. The revert is recorded as a new revision.
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Param number path int true "Revision number"
+// @Success 200 {object} models.BlogPost
+// @Router /api/blogs/{id}/revisions/{number}/revert [post]
+func (h *RevisionHandler) RevertBlog(c *fiber.Ctx) error {
+	number, err := strconv.Atoi(c.Params("number"))
+	if err != nil || number < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid revision number",
+		})
+	}
+
+	blog, err := h.blogService.RevertBlogPost(c.Params("id"), number, currentUserID(c))
+	if err != nil {
+		return c.Status(revisionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(blog)
+}
+
+func (h *RevisionHandler) getRevisions(c *fiber.Ctx, ownerType string) error {
+	if err := h.checkReadable(c, ownerType); err != nil {
+		return c.Status(revisionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	revisions, err := h.service.GetRevisions(ownerType, c.Params("id"))
+	if err != nil {
+		return c.Status(revisionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(revisions)
+}
+
+func (h *RevisionHandler) getRevision(c *fiber.Ctx, ownerType string) error {
+	if err := h.checkReadable(c, ownerType); err != nil {
+		return c.Status(revisionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	number, err := strconv.Atoi(c.Params("number"))
+	if err != nil || number < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid revision number",
+		})
+	}
+
+	revision, err := h.service.GetRevision(ownerType, c.Params("id"), number)
+	if err != nil {
+		return c.Status(revisionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(revision)
+}
+
+func (h *RevisionHandler) diffRevisions(c *fiber.Ctx, ownerType string) error {
+	if err := h.checkReadable(c, ownerType); err != nil {
+		return c.Status(revisionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	from, fromErr := strconv.Atoi(c.Query("from"))
+	to, toErr := strconv.Atoi(c.Query("to"))
+	if fromErr != nil || toErr != nil || from < 1 || to < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "from and to must be revision numbers",
+		})
+	}
+
+	diff, err := h.service.DiffRevisions(ownerType, c.Params("id"), from, to)
+	if err != nil {
+		return c.Status(revisionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(diff)
+}
+
+// checkReadable hides the history of blog posts the request may not read
+func (h *RevisionHandler) checkReadable(c *fiber.Ctx, ownerType string) error {
+	if ownerType != models.RevisionOwnerBlog {
+		return nil
+	}
+	return checkBlogReadable(c, h.blogService, c.Params("id"))
+}
+
+// revisionErrorStatus maps revision and revert errors to HTTP status codes
+func revisionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrRevisionNotFound), errors.Is(err, service.ErrTodoNotFound),
+		errors.Is(err, service.ErrBlogPostNotFound):
+		return fiber.StatusNotFound
+	}
+	return todoErrorStatus(err, fiber.StatusInternalServerError)
+}
@@ -5,25 +5,133 @@ import (
 )
 
 // SetupRoutes 는 애플리케이션의 모든 라우트를 설정합니다
-func SetupRoutes(app *fiber.App, todoHandler *TodoHandler, blogHandler *BlogHandler) {
+func SetupRoutes(app *fiber.App, todoHandler *TodoHandler, blogHandler *BlogHandler, listHandler *ListHandler, tagHandler *TagHandler, timeHandler *TimeEntryHandler, commentHandler *CommentHandler, attachmentHandler *AttachmentHandler, trashHandler *TrashHandler, revisionHandler *RevisionHandler, undoHandler *UndoHandler, templateHandler *TemplateHandler, viewHandler *ViewHandler, searchHandler *SearchHandler) {
 	// API 라우트 그룹
 	api := app.Group("/api")
 
 	// Todo 관련 라우트
 	todos := api.Group("/todos")
-	todos.Post("/", todoHandler.CreateTodo)      // Todo 생성
-	todos.Get("/", todoHandler.GetAllTodos)      // 전체 Todo 조회
-	todos.Get("/:id", todoHandler.GetTodo)       // 특정 Todo 조회
-	todos.Put("/:id", todoHandler.UpdateTodo)    // Todo 수정
-	todos.Delete("/:id", todoHandler.DeleteTodo) // Todo 삭제
+	todos.Post("/", todoHandler.CreateTodo)                       // Todo 생성
+	todos.Get("/", todoHandler.GetAllTodos)                       // 전체 Todo 조회
+	todos.Post("/reorder", todoHandler.ReorderTodos)              // Todo 일괄 순서 변경
+	todos.Post("/bulk", todoHandler.BulkTodos)                    // Todo 일괄 작업 (완료/삭제/이동/태그)
+	todos.Post("/quick-add", todoHandler.QuickAdd)                // 한 줄 입력으로 Todo 생성
+	todos.Post("/quick-add/preview", todoHandler.PreviewQuickAdd) // 한 줄 입력 해석 미리보기
+	todos.Get("/board", todoHandler.GetBoard)                     // 상태별 보드 조회
+	todos.Get("/archive", todoHandler.GetArchivedTodos)           // 보관된 Todo 조회
+	todos.Get("/:id", todoHandler.GetTodo)                        // 특정 Todo 조회
+	todos.Put("/:id", todoHandler.UpdateTodo)                     // Todo 수정
+	todos.Delete("/:id", todoHandler.DeleteTodo)                  // Todo 삭제 (하위 포함)
+
+	todos.Post("/:id/subtasks", todoHandler.CreateSubtask)  // 하위 Todo 생성
+	todos.Get("/:id/subtasks", todoHandler.GetSubtasks)     // 직계 하위 Todo 조회
+	todos.Get("/:id/tree", todoHandler.GetTodoTree)         // Todo 트리 조회
+	todos.Post("/:id/move", todoHandler.MoveTodo)           // 다른 목록으로 이동
+	todos.Post("/:id/reorder", todoHandler.ReorderTodo)     // 다른 Todo 앞/뒤로 이동
+	todos.Post("/:id/status", todoHandler.ChangeStatus)     // 워크플로 상태 전환
+	todos.Post("/:id/archive", todoHandler.ArchiveTodo)     // 보관 (하위 포함)
+	todos.Post("/:id/unarchive", todoHandler.UnarchiveTodo) // 보관 해제 (하위 포함)
+
+	todos.Post("/:id/dependencies", todoHandler.AddDependency)                 // 선행 Todo 추가
+	todos.Delete("/:id/dependencies/:blockerId", todoHandler.RemoveDependency) // 선행 Todo 제거
+
+	todos.Post("/:id/timer/start", timeHandler.StartTimer)         // 타이머 시작
+	todos.Post("/:id/timer/stop", timeHandler.StopTimer)           // 타이머 정지
+	todos.Get("/:id/time-entries", timeHandler.GetTodoTimeEntries) // 시간 기록 조회
+	todos.Post("/:id/time-entries", timeHandler.CreateTimeEntry)   // 시간 기록 수동 추가
+
+	todos.Post("/:id/comments", commentHandler.CreateComment)              // 댓글 작성
+	todos.Get("/:id/comments", commentHandler.GetComments)                 // 댓글 조회
+	todos.Put("/:id/comments/:commentId", commentHandler.UpdateComment)    // 댓글 수정
+	todos.Delete("/:id/comments/:commentId", commentHandler.DeleteComment) // 댓글 삭제
+	todos.Get("/:id/activity", commentHandler.GetTimeline)                 // 댓글 + 변경 내역 타임라인
+
+	todos.Post("/:id/attachments", attachmentHandler.UploadTodoAttachment) // 첨부 파일 업로드
+	todos.Get("/:id/attachments", attachmentHandler.GetTodoAttachments)    // 첨부 파일 목록
+
+	todos.Get("/:id/revisions", revisionHandler.GetTodoRevisions)           // 수정 이력 조회
+	todos.Get("/:id/revisions/diff", revisionHandler.DiffTodoRevisions)     // 두 이력 비교 (from, to)
+	todos.Get("/:id/revisions/:number", revisionHandler.GetTodoRevision)    // 특정 이력 조회
+	todos.Post("/:id/revisions/:number/revert", revisionHandler.RevertTodo) // 이력 시점으로 되돌리기
+
+	// 첨부 파일 관련 라우트
+	attachments := api.Group("/attachments")
+	attachments.Get("/:id", attachmentHandler.GetAttachment)               // 첨부 파일 정보
+	attachments.Get("/:id/download", attachmentHandler.DownloadAttachment) // 다운로드 (Range 지원)
+	attachments.Delete("/:id", attachmentHandler.DeleteAttachment)         // 첨부 파일 삭제
+
+	// 시간 기록 관련 라우트
+	timeEntries := api.Group("/time-entries")
+	timeEntries.Get("/running", timeHandler.GetRunningTimer) // 실행 중인 타이머 조회
+	timeEntries.Get("/report", timeHandler.GetTimeReport)    // 할 일/목록/일자별 시간 집계
+	timeEntries.Delete("/:id", timeHandler.DeleteTimeEntry)  // 시간 기록 삭제
+
+	// List 관련 라우트
+	lists := api.Group("/lists")
+	lists.Post("/", listHandler.CreateList)              // List 생성
+	lists.Get("/", listHandler.GetAllLists)              // 전체 List 조회 (집계 포함)
+	lists.Get("/:id", listHandler.GetList)               // 특정 List 조회 (집계 포함)
+	lists.Put("/:id", listHandler.UpdateList)            // List 수정
+	lists.Delete("/:id", listHandler.DeleteList)         // List 삭제
+	lists.Get("/:id/todos", listHandler.GetListTodos)    // List 의 Todo 조회
+	lists.Post("/:id/todos", listHandler.CreateListTodo) // List 에 Todo 생성
+
+	// Tag 관련 라우트
+	tags := api.Group("/tags")
+	tags.Get("/", tagHandler.GetAllTags)         // 전체 Tag 조회 (사용 횟수 포함)
+	tags.Put("/:id", tagHandler.RenameTag)       // Tag 이름 변경
+	tags.Post("/:id/merge", tagHandler.MergeTag) // 다른 Tag 로 병합
+
+	// 보기(스마트 보기 + 저장된 필터) 관련 라우트
+	views := api.Group("/views")
+	views.Get("/", viewHandler.GetViews)              // 보기 목록 (개수 포함)
+	views.Get("/:id/todos", viewHandler.GetViewTodos) // 보기의 Todo 조회
+
+	filters := api.Group("/filters")
+	filters.Get("/", viewHandler.GetFilters)         // 저장된 필터 조회
+	filters.Post("/", viewHandler.CreateFilter)      // 필터 저장
+	filters.Put("/:id", viewHandler.UpdateFilter)    // 필터 수정
+	filters.Delete("/:id", viewHandler.DeleteFilter) // 필터 삭제
+
+	// Template 관련 라우트
+	templates := api.Group("/templates")
+	templates.Post("/", templateHandler.CreateTemplate)                     // Template 생성
+	templates.Get("/", templateHandler.GetAllTemplates)                     // 전체 Template 조회
+	templates.Get("/:id", templateHandler.GetTemplate)                      // 특정 Template 조회
+	templates.Put("/:id", templateHandler.UpdateTemplate)                   // Template 수정
+	templates.Delete("/:id", templateHandler.DeleteTemplate)                // Template 삭제
+	templates.Post("/:id/instantiate", templateHandler.InstantiateTemplate) // Template 으로 Todo 생성 (하위 포함)
 
 	// Blog 관련 라우트
 	blogs := api.Group("/blogs")
-	blogs.Post("/", blogHandler.CreateBlog)      // Blog 생성
-	blogs.Get("/", blogHandler.GetAllBlogs)      // 전체 Blog 조회
-	blogs.Get("/:id", blogHandler.GetBlog)       // 특정 Blog 조회
-	blogs.Put("/:id", blogHandler.UpdateBlog)    // Blog 수정
-	blogs.Delete("/:id", blogHandler.DeleteBlog) // Blog 삭제
+	blogs.Post("/", blogHandler.CreateBlog)                // Blog 생성
+	blogs.Get("/", blogHandler.GetAllBlogs)                // 전체 Blog 조회
+	blogs.Get("/by-slug/:slug", blogHandler.GetBlogBySlug) // 슬러그로 Blog 조회 (이전 슬러그는 301)
+	blogs.Get("/:id", blogHandler.GetBlog)                 // 특정 Blog 조회
+	blogs.Put("/:id", blogHandler.UpdateBlog)              // Blog 수정
+	blogs.Delete("/:id", blogHandler.DeleteBlog)           // Blog 삭제
+
+	blogs.Post("/:id/status", blogHandler.ChangeBlogStatus) // 게시 상태 변경 (draft, scheduled, published, unlisted)
+
+	blogs.Post("/:id/attachments", attachmentHandler.UploadBlogAttachment) // 첨부 파일 업로드
+	blogs.Get("/:id/attachments", attachmentHandler.GetBlogAttachments)    // 첨부 파일 목록
+
+	blogs.Get("/:id/revisions", revisionHandler.GetBlogRevisions)           // 초안 이력 조회
+	blogs.Get("/:id/revisions/diff", revisionHandler.DiffBlogRevisions)     // 두 이력 비교 (from, to)
+	blogs.Get("/:id/revisions/:number", revisionHandler.GetBlogRevision)    // 특정 이력 조회
+	blogs.Post("/:id/revisions/:number/revert", revisionHandler.RevertBlog) // 이력 시점으로 되돌리기
+
+	// 휴지통 관련 라우트
+	trash := api.Group("/trash")
+	trash.Get("/", trashHandler.GetTrash)                      // 휴지통 조회
+	trash.Post("/todos/:id/restore", trashHandler.RestoreTodo) // Todo 복원 (함께 삭제된 하위 포함)
+	trash.Post("/blogs/:id/restore", trashHandler.RestoreBlog) // Blog 복원
+
+	// 검색 라우트
+	api.Get("/search", searchHandler.Search) // Todo·Blog 전문 검색 (q, type, limit, offset)
+
+	// 되돌리기 라우트
+	api.Post("/undo/:token", undoHandler.Undo) // 삭제/수정/상태 전환/이동/순서 변경/일괄 작업 되돌리기
 
 	// 헬스 체크 엔드포인트
 	app.Get("/health", func(c *fiber.Ctx) error {
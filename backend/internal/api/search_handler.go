@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testbox/internal/repository"
+	"testbox/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchHandler struct {
+	service service.SearchService
+}
+
+func NewSearchHandler(service service.SearchService) *SearchHandler {
+	return &SearchHandler{service: service}
+}
+
+// Search runs a full-text search across todos and blog posts
+// @Summary Search todos and blog posts
+// @Description Searches titles, content and tag names, best match first. q accepts web search syntax ("quoted phrases", OR, -excluded). Title and snippet are HTML-escaped with matches wrapped in <mark>. Anonymous readers (no X-User-ID) only find published blog posts.
+// @Tags search
+// @Produce json
+// @Param q query string true "Search text"
+// @Param type query string false "Comma-separated result types: todo, blog (default: both)"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of results to skip"
+// @Success 200 {object} repository.SearchPage
+// @Router /api/search [get]
+func (h *SearchHandler) Search(c *fiber.Ctx) error {
+	var types []string
+	for _, t := range strings.Split(c.Query("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > repository.MaxSearchPageSize {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "limit must be between 1 and 100",
+			})
+		}
+	}
+
+	offset := 0
+	if raw := c.Query("offset"); raw != "" {
+		var err error
+		offset, err = strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "offset must be a non-negative integer",
+			})
+		}
+	}
+
+	page, err := h.service.Search(c.Query("q"), types, limit, offset, currentUserID(c) == "")
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, service.ErrEmptySearch) || errors.Is(err, service.ErrInvalidSearchType) {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(page)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testbox/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TagHandler struct {
+	service service.TagService
+}
+
+func NewTagHandler(service service.TagService) *TagHandler {
+	return &TagHandler{service: service}
+}
+
+type RenameTagRequest struct {
+	Name string `json:"name"`
+}
+
+type MergeTagRequest struct {
+	TargetID string `json:"target_id"`
+}
+
+// GetAllTags retrieves all tags with usage counts
+// @Summary Get all tags
+// @Description Retrieves every tag with the number of todos and blog posts using it
+// @Tags tags
+// @Produce json
+// @Success 200 {array} models.TagUsage
+// @Router /api/tags [get]
+func (h *TagHandler) GetAllTags(c *fiber.Ctx) error {
+	tags, err := h.service.GetAllTags()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(tags)
+}
+
+// RenameTag renames a tag
+// @Summary Rename tag
+// @Description Renames a tag everywhere it is used; fails if the new name already exists (use merge instead)
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Success 200 {object} models.Tag
+// @Router /api/tags/{id} [put]
+func (h *TagHandler) RenameTag(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req RenameTagRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Name is required",
+		})
+	}
+
+	tag, err := h.service.RenameTag(id, req.Name)
+	if err != nil {
+		return c.Status(tagErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(tag)
+}
+
+// MergeTag merges a tag into another tag
+// @Summary Merge tags
+// @Description Moves all todos and blog posts from the tag to the target tag and deletes the tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Source Tag ID"
+// @Success 200 {object} models.Tag
+// @Router /api/tags/{id}/merge [post]
+func (h *TagHandler) MergeTag(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req MergeTagRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.TargetID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "target_id is required",
+		})
+	}
+
+	tag, err := h.service.MergeTags(id, req.TargetID)
+	if err != nil {
+		return c.Status(tagErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(tag)
+}
+
+// tagErrorStatus maps tag service errors to HTTP status codes
+func tagErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTagNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrTagExists):
+		return fiber.StatusConflict
+	case errors.Is(err, service.ErrTagMergeSelf):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// parseTagQuery splits a comma-separated ?tags= value into tag names
+func parseTagQuery(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, ",")
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"errors"
+	"testbox/internal/models"
+	"testbox/internal/service"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TemplateHandler struct {
+	service service.TemplateService
+}
+
+func NewTemplateHandler(service service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{service: service}
+}
+
+type TemplateRequest struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Todo        models.TemplateItem `json:"todo"`
+}
+
+type InstantiateTemplateRequest struct {
+	StartDate string            `json:"start_date"` // RFC3339 or YYYY-MM-DD (default: today)
+	ListID    *string           `json:"list_id"`
+	Variables map[string]string `json:"variables"`
+}
+
+// CreateTemplate creates a new template
+// @Summary Create a template
+// @Description Creates a reusable todo with subtasks. Due dates are given as offsets in days from the start date, and titles and content may contain {{variable}} placeholders.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Template
+// @Router /api/templates [post]
+func (h *TemplateHandler) CreateTemplate(c *fiber.Ctx) error {
+	var req TemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	template, err := h.service.CreateTemplate(service.TemplateInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Todo:        req.Todo,
+	})
+	if err != nil {
+		return c.Status(templateErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(template)
+}
+
+// GetTemplate retrieves a template
+// @Summary Get template by ID
+// @Description Retrieves a template with its items and the variables they use
+// @Tags templates
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} models.Template
+// @Router /api/templates/{id} [get]
+func (h *TemplateHandler) GetTemplate(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	template, err := h.service.GetTemplate(id)
+	if err != nil {
+		return c.Status(templateErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(template)
+}
+
+// GetAllTemplates retrieves all templates
+// @Summary Get all templates
+// @Description Retrieves all templates ordered by name
+// @Tags templates
+// @Produce json
+// @Success 200 {array} models.Template
+// @Router /api/templates [get]
+func (h *TemplateHandler) GetAllTemplates(c *fiber.Ctx) error {
+	templates, err := h.service.GetAllTemplates()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(templates)
+}
+
+// UpdateTemplate replaces an existing template
+// @Summary Update template
+// @Description Replaces name, description and items of a template; todos created from it are not changed
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} models.Template
+// @Router /api/templates/{id} [put]
+func (h *TemplateHandler) UpdateTemplate(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req TemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	template, err := h.service.UpdateTemplate(id, service.TemplateInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Todo:        req.Todo,
+	})
+	if err != nil {
+		return c.Status(templateErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(template)
+}
+
+// DeleteTemplate deletes a template
+// @Summary Delete template
+// @Description Deletes a template; todos created from it are kept
+// @Tags templates
+// @Param id path string true "Template ID"
+// @Success 204
+// @Router /api/templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.service.DeleteTemplate(id); err != nil {
+		return c.Status(templateErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// InstantiateTemplate creates todos from a template
+// @Summary Instantiate a template
+// @Description Creates the template's todo with all of its subtasks. Due dates are the start date plus each item's offset, and {{variable}} placeholders are replaced with the given values ({{date}} defaults to the start date). Fails with 400 if a variable has no value.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 201 {object} models.TodoNode
+// @Router /api/templates/{id}/instantiate [post]
+func (h *TemplateHandler) InstantiateTemplate(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req InstantiateTemplateRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	start := time.Now()
+	if req.StartDate != "" {
+		t, err := parseQueryTime(req.StartDate)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid start_date (use RFC3339 or YYYY-MM-DD)",
+			})
+		}
+		start = t
+	}
+
+	tree, err := h.service.Instantiate(id, service.InstantiateInput{
+		Start:     start,
+		ListID:    req.ListID,
+		Variables: req.Variables,
+		Actor:     currentUserID(c),
+	})
+	if err != nil {
+		return c.Status(templateErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(tree)
+}
+
+func templateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrInvalidTemplate), errors.Is(err, service.ErrMissingTemplateVariable):
+		return fiber.StatusBadRequest
+	}
+	return todoErrorStatus(err, fiber.StatusInternalServerError)
+}
@@ -0,0 +1,296 @@
+package api
+
+import (
+	"errors"
+	"testbox/internal/repository"
+	"testbox/internal/service"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TimeEntryHandler struct {
+	service service.TimeEntryService
+}
+
+func NewTimeEntryHandler(service service.TimeEntryService) *TimeEntryHandler {
+	return &TimeEntryHandler{service: service}
+}
+
+type StartTimerRequest struct {
+	Note string `json:"note"`
+}
+
+type ManualEntryRequest struct {
+	StartedAt *time.Time `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Duration  int64      `json:"duration_seconds"` // used when ended_at is omitted
+	Note      string     `json:"note"`
+}
+
+// StartTimer starts a timer on a todo for the current user
+// @Summary Start a timer
+// @Description Starts tracking time on the todo; each user (X-User-ID header) can run only one timer at a time
+// @Tags time
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param X-User-ID header string true "User ID"
+// @Success 201 {object} models.TimeEntry
+// @Router /api/todos/{id}/timer/start [post]
+func (h *TimeEntryHandler) StartTimer(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "X-User-ID header is required",
+		})
+	}
+
+	// 본문은 선택 사항입니다
+	var req StartTimerRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	entry, err := h.service.StartTimer(c.Params("id"), userID, req.Note)
+	if err != nil {
+		return c.Status(timeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entry)
+}
+
+// StopTimer stops the current user's running timer on a todo
+// @Summary Stop a timer
+// @Description Stops the current user's running timer on the todo and records its duration
+// @Tags time
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param X-User-ID header string true "User ID"
+// @Success 200 {object} models.TimeEntry
+// @Router /api/todos/{id}/timer/stop [post]
+func (h *TimeEntryHandler) StopTimer(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "X-User-ID header is required",
+		})
+	}
+
+	entry, err := h.service.StopTimer(c.Params("id"), userID)
+	if err != nil {
+		return c.Status(timeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(entry)
+}
+
+// GetRunningTimer retrieves the current user's running timer
+// @Summary Get the running timer
+// @Description Retrieves the current user's running timer, or 404 if none is running
+// @Tags time
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Success 200 {object} models.TimeEntry
+// @Router /api/time-entries/running [get]
+func (h *TimeEntryHandler) GetRunningTimer(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "X-User-ID header is required",
+		})
+	}
+
+	entry, err := h.service.GetRunningTimer(userID)
+	if err != nil {
+		return c.Status(timeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(entry)
+}
+
+// CreateTimeEntry records time on a todo manually
+// @Summary Add a manual time entry
+// @Description Records a finished span of time given by started_at and either ended_at or duration_seconds
+// @Tags time
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param X-User-ID header string true "User ID"
+// @Success 201 {object} models.TimeEntry
+// @Router /api/todos/{id}/time-entries [post]
+func (h *TimeEntryHandler) CreateTimeEntry(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "X-User-ID header is required",
+		})
+	}
+
+	var req ManualEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.StartedAt == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "started_at is required",
+		})
+	}
+	if (req.EndedAt == nil) == (req.Duration <= 0) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Exactly one of ended_at or a positive duration_seconds is required",
+		})
+	}
+
+	endedAt := req.StartedAt.Add(time.Duration(req.Duration) * time.Second)
+	if req.EndedAt != nil {
+		endedAt = *req.EndedAt
+	}
+
+	entry, err := h.service.AddManualEntry(c.Params("id"), userID, *req.StartedAt, endedAt, req.Note)
+	if err != nil {
+		return c.Status(timeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entry)
+}
+
+// GetTodoTimeEntries retrieves the time entries of a todo
+// @Summary Get time entries of a todo
+// @Description Retrieves all time entries of the todo, newest first
+// @Tags time
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {array} models.TimeEntry
+// @Router /api/todos/{id}/time-entries [get]
+func (h *TimeEntryHandler) GetTodoTimeEntries(c *fiber.Ctx) error {
+	entries, err := h.service.GetTodoEntries(c.Params("id"))
+	if err != nil {
+		return c.Status(timeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(entries)
+}
+
+// DeleteTimeEntry deletes a time entry
+// @Summary Delete a time entry
+// @Description Deletes a time entry; deleting a running timer discards it
+// @Tags time
+// @Param id path string true "Time entry ID"
+// @Success 204
+// @Router /api/time-entries/{id} [delete]
+func (h *TimeEntryHandler) DeleteTimeEntry(c *fiber.Ctx) error {
+	if err := h.service.DeleteEntry(c.Params("id")); err != nil {
+		return c.Status(timeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetTimeReport sums tracked time per todo, list or day
+// @Summary Get a time report
+// @Description Sums tracked time (running timers count up to now) grouped by todo, list or day
+// @Tags time
+// @Produce json
+// @Param group_by query string false "Grouping (todo, list, day; default todo)"
+// @Param user_id query string false "Only entries of this user"
+// @Param todo_id query string false "Only entries of this todo"
+// @Param list_id query string false "Only entries of todos in this list (none for todos without a list)"
+// @Param from query string false "Entries started at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Entries started before (RFC3339 or YYYY-MM-DD)"
+// @Param tz query string false "IANA time zone for day grouping (default UTC)"
+// @Success 200 {array} models.TimeReportRow
+// @Router /api/time-entries/report [get]
+func (h *TimeEntryHandler) GetTimeReport(c *fiber.Ctx) error {
+	query := repository.TimeReportQuery{
+		GroupBy:  c.Query("group_by", repository.TimeReportByTodo),
+		UserID:   c.Query("user_id"),
+		TodoID:   c.Query("todo_id"),
+		Location: c.Query("tz", "UTC"),
+	}
+
+	switch query.GroupBy {
+	case repository.TimeReportByTodo, repository.TimeReportByList, repository.TimeReportByDay:
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "group_by must be one of todo, list, day",
+		})
+	}
+
+	if _, err := time.LoadLocation(query.Location); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid time zone",
+		})
+	}
+
+	if raw := c.Query("list_id"); raw != "" {
+		listID := raw
+		if raw == "none" {
+			listID = ""
+		}
+		query.ListID = &listID
+	}
+
+	dateParams := []struct {
+		name   string
+		target **time.Time
+	}{
+		{"from", &query.From},
+		{"to", &query.To},
+	}
+	for _, p := range dateParams {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := parseQueryTime(raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": p.name + " must be RFC3339 or YYYY-MM-DD",
+			})
+		}
+		*p.target = &t
+	}
+
+	rows, err := h.service.GetReport(query)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(rows)
+}
+
+// timeErrorStatus maps time tracking errors to HTTP status codes
+func timeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTodoNotFound), errors.Is(err, service.ErrTimeEntryNotFound),
+		errors.Is(err, service.ErrNoRunningTimer):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrTimerRunning):
+		return fiber.StatusConflict
+	case errors.Is(err, service.ErrInvalidTimeRange):
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
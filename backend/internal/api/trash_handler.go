@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"testbox/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TrashHandler struct {
+	service     service.TrashService
+	todoService service.TodoService
+	blogService service.BlogService
+}
+
+func NewTrashHandler(service service.TrashService, todoService service.TodoService, blogService service.BlogService) *TrashHandler {
+	return &TrashHandler{service: service, todoService: todoService, blogService: blogService}
+}
+
+// GetTrash lists deleted todos and blog posts
+// @Summary Get trash
+// @Description Lists soft-deleted todos and blog posts that can still be restored. Subtasks deleted with their parent are restored with it and not listed separately.
+// @Tags trash
+// @Produce json
+// @Success 200 {object} models.Trash
+// @Router /api/trash [get]
+func (h *TrashHandler) GetTrash(c *fiber.Ctx) error {
+	trash, err := h.service.GetTrash()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(trash)
+}
+
+// RestoreTodo restores a deleted todo together with the subtasks deleted with it
+// @Summary Restore a todo
+// @Description Restores a todo from the trash; fails with 409 while its parent is still in the trash
+// @Tags trash
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {object} models.Todo
+// @Router /api/trash/todos/{id}/restore [post]
+func (h *TrashHandler) RestoreTodo(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	todo, err := h.todoService.RestoreTodo(id)
+	if err != nil {
+		return c.Status(trashErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(todo)
+}
+
+// RestoreBlog restores a deleted blog post
+// @Summary Restore a blog post
+// @Description Restores a blog post from the trash
+// @Tags trash
+// @Produce json
+// @Param id path string true "Blog ID"
+// @Success 200 {object} models.BlogPost
+// @Router /api/trash/blogs/{id}/restore [post]
+func (h *TrashHandler) RestoreBlog(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	blog, err := h.blogService.RestoreBlogPost(id)
+	if err != nil {
+		return c.Status(trashErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(blog)
+}
+
+// trashErrorStatus maps restore errors to HTTP status codes
+func trashErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTodoNotInTrash), errors.Is(err, service.ErrBlogPostNotInTrash):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrParentInTrash):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
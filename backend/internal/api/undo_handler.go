@@ -0,0 +1,58 @@
+package api
+
+import (
+	"errors"
+	"testbox/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// undoTokenHeader carries the undo token of a successful mutation
+const undoTokenHeader = "X-Undo-Token"
+
+type UndoHandler struct {
+	service service.UndoService
+}
+
+func NewUndoHandler(service service.UndoService) *UndoHandler {
+	return &UndoHandler{service: service}
+}
+
+// Undo reverses the operation that returned the token
+// @Summary Undo an operation
+// @Description Reverses a delete, update, status change, move, reorder or bulk operation using the token from its X-Undo-Token response header. Tokens expire after UNDO_WINDOW_SECONDS and can be used once.
+// @Tags undo
+// @Produce json
+// @Param token path string true "Undo token"
+// @Success 200 {object} service.UndoResult
+// @Router /api/undo/{token} [post]
+func (h *UndoHandler) Undo(c *fiber.Ctx) error {
+	token := c.Params("token")
+
+	result, err := h.service.Undo(token, currentUserID(c))
+	if err != nil {
+		return c.Status(undoErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// setUndoToken stores the captured state and returns its token in the response header
+func setUndoToken(c *fiber.Ctx, undo service.UndoService, entry *service.UndoEntry) {
+	if token := undo.Commit(entry); token != "" {
+		c.Set(undoTokenHeader, token)
+	}
+}
+
+// undoErrorStatus maps undo errors to HTTP status codes
+func undoErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUndoExpired):
+		return fiber.StatusGone
+	case errors.Is(err, service.ErrTodoNotInTrash), errors.Is(err, service.ErrParentInTrash):
+		return fiber.StatusConflict
+	}
+	return todoErrorStatus(err, fiber.StatusInternalServerError)
+}
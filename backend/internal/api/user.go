@@ -0,0 +1,15 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// userIDHeader identifies the acting user until real authentication exists
+const userIDHeader = "X-User-ID"
+
+// currentUserID returns the acting user's ID from the request header ("" if absent)
+func currentUserID(c *fiber.Ctx) string {
+	return strings.TrimSpace(c.Get(userIDHeader))
+}
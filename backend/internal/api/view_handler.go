@@ -0,0 +1,213 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+	"testbox/internal/service"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ViewHandler struct {
+	service service.ViewService
+}
+
+func NewViewHandler(service service.ViewService) *ViewHandler {
+	return &ViewHandler{service: service}
+}
+
+type SavedFilterRequest struct {
+	Name   string            `json:"name"`
+	Filter models.TodoFilter `json:"filter"`
+}
+
+// GetViews lists the smart views and the user's saved filters with their counts
+// @Summary Get views
+// @Description Lists the smart views (today, upcoming, overdue, completed-this-week) followed by the saved filters of the X-User-ID user, each with the number of todos it currently shows
+// @Tags views
+// @Produce json
+// @Param tz query string false "IANA time zone deciding where days and weeks start (default: server time zone)"
+// @Success 200 {array} models.View
+// @Router /api/views [get]
+func (h *ViewHandler) GetViews(c *fiber.Ctx) error {
+	now, ok := viewNow(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid time zone",
+		})
+	}
+
+	views, err := h.service.GetViews(currentUserID(c), now)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(views)
+}
+
+// GetViewTodos retrieves the todos of a smart view or saved filter
+// @Summary Get todos in a view
+// @Description Retrieves a page of the todos shown by a smart view key or saved filter ID; total is the view's count
+// @Tags views
+// @Produce json
+// @Param id path string true "Smart view key or saved filter ID"
+// @Param tz query string false "IANA time zone deciding where days and weeks start (default: server time zone)"
+// @Param cursor query string false "Cursor from a previous page"
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Success 200 {object} repository.TodoPage
+// @Router /api/views/{id}/todos [get]
+func (h *ViewHandler) GetViewTodos(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	now, ok := viewNow(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid time zone",
+		})
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > repository.MaxTodoPageSize {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "limit must be between 1 and 200",
+			})
+		}
+	}
+
+	page, err := h.service.GetViewTodos(id, currentUserID(c), now, c.Query("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid cursor",
+			})
+		}
+		return c.Status(viewErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(page)
+}
+
+// GetFilters retrieves the user's saved filters
+// @Summary Get saved filters
+// @Description Retrieves the saved filters of the X-User-ID user, oldest first
+// @Tags views
+// @Produce json
+// @Success 200 {array} models.SavedFilter
+// @Router /api/filters [get]
+func (h *ViewHandler) GetFilters(c *fiber.Ctx) error {
+	filters, err := h.service.GetFilters(currentUserID(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(filters)
+}
+
+// CreateFilter saves a filter for the user
+// @Summary Create a saved filter
+// @Description Saves a named filter with the options of GET /api/todos (due, due_in_days, priority, completed, status, list_id, tags, root_only, q, archived, sort, order). Date windows are evaluated whenever the view is opened.
+// @Tags views
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.SavedFilter
+// @Router /api/filters [post]
+func (h *ViewHandler) CreateFilter(c *fiber.Ctx) error {
+	var req SavedFilterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	filter, err := h.service.CreateFilter(currentUserID(c), req.Name, req.Filter)
+	if err != nil {
+		return c.Status(viewErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(filter)
+}
+
+// UpdateFilter replaces a saved filter
+// @Summary Update a saved filter
+// @Description Replaces the name and options of a saved filter of the X-User-ID user
+// @Tags views
+// @Accept json
+// @Produce json
+// @Param id path string true "Saved filter ID"
+// @Success 200 {object} models.SavedFilter
+// @Router /api/filters/{id} [put]
+func (h *ViewHandler) UpdateFilter(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req SavedFilterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	filter, err := h.service.UpdateFilter(id, currentUserID(c), req.Name, req.Filter)
+	if err != nil {
+		return c.Status(viewErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(filter)
+}
+
+// DeleteFilter deletes a saved filter
+// @Summary Delete a saved filter
+// @Description Deletes a saved filter of the X-User-ID user
+// @Tags views
+// @Param id path string true "Saved filter ID"
+// @Success 204
+// @Router /api/filters/{id} [delete]
+func (h *ViewHandler) DeleteFilter(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.service.DeleteFilter(id, currentUserID(c)); err != nil {
+		return c.Status(viewErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// viewNow returns the current time in the time zone of the tz query
+func viewNow(c *fiber.Ctx) (time.Time, bool) {
+	now := time.Now()
+	if tz := c.Query("tz"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return now, false
+		}
+		now = now.In(loc)
+	}
+	return now, true
+}
+
+func viewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrViewNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrInvalidFilter):
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
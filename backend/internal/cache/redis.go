@@ -92,6 +92,114 @@ func (r *RedisCache) DeleteTodo(id string) error {
 	return nil
 }
 
+// DeleteTodos removes several todos from cache in a single round trip
+func (r *RedisCache) DeleteTodos(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = fmt.Sprintf("todo:%s", id)
+	}
+
+	if err := r.client.Del(r.ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to delete from cache: %w", err)
+	}
+
+	log.Printf("✓ Cache DELETE: %d todos", len(ids))
+	return nil
+}
+
+// GetListCounts returns the cached open/done counts of a list, or nil on a miss
+func (r *RedisCache) GetListCounts(listID string) (*models.ListCounts, error) {
+	key := fmt.Sprintf("list:%s:counts", listID)
+
+	data, err := r.client.Get(r.ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get error: %w", err)
+	}
+
+	var counts models.ListCounts
+	if err := json.Unmarshal([]byte(data), &counts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal list counts: %w", err)
+	}
+	return &counts, nil
+}
+
+// SetListCounts caches the open/done counts of a list
+func (r *RedisCache) SetListCounts(listID string, counts *models.ListCounts) error {
+	key := fmt.Sprintf("list:%s:counts", listID)
+
+	data, err := json.Marshal(counts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal list counts: %w", err)
+	}
+
+	if err := r.client.Set(r.ctx, key, data, r.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set cache: %w", err)
+	}
+	return nil
+}
+
+// InvalidateListCounts drops the cached counts of the given lists
+func (r *RedisCache) InvalidateListCounts(listIDs ...string) error {
+	if len(listIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(listIDs))
+	for i, id := range listIDs {
+		keys[i] = fmt.Sprintf("list:%s:counts", id)
+	}
+
+	if err := r.client.Del(r.ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to delete from cache: %w", err)
+	}
+
+	log.Printf("✓ Cache DELETE: counts of %d lists", len(listIDs))
+	return nil
+}
+
+// SetUndo stores an undo entry that expires after ttl
+func (r *RedisCache) SetUndo(token string, data []byte, ttl time.Duration) error {
+	key := fmt.Sprintf("undo:%s", token)
+
+	if err := r.client.Set(r.ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set cache: %w", err)
+	}
+	return nil
+}
+
+// TakeUndo returns and removes an undo entry so it can be used only once (nil if missing or expired).
+// It also returns the time the entry had left so a failed undo can put it back with SetUndo.
+func (r *RedisCache) TakeUndo(token string) ([]byte, time.Duration, error) {
+	key := fmt.Sprintf("undo:%s", token)
+
+	var ttl *redis.DurationCmd
+	var get *redis.StringCmd
+	_, err := r.client.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		ttl = pipe.PTTL(r.ctx, key)
+		get = pipe.GetDel(r.ctx, key)
+		return nil
+	})
+	if err == redis.Nil {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("redis getdel error: %w", err)
+	}
+
+	data, err := get.Bytes()
+	if err != nil {
+		return nil, 0, fmt.Errorf("redis getdel error: %w", err)
+	}
+	return data, ttl.Val(), nil
+}
+
 // InvalidateAll clears all todo caches
 func (r *RedisCache) InvalidateAll() error {
 	iter := r.client.Scan(r.ctx, 0, "todo:*", 0).Iterator()
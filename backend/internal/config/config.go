@@ -3,6 +3,10 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"testbox/internal/workflow"
+	"time"
 )
 
 type Config struct {
@@ -28,6 +32,40 @@ type Config struct {
 	// MongoDB
 	MongoURL string
 	MongoDB  string
+
+	// Scheduler
+	SchedulerInterval time.Duration // how often due jobs are polled
+	ReminderLead      time.Duration // reminder offset before a todo's due date
+
+	// Todo workflow
+	WorkflowSpec    string // allowed status transitions, e.g. "todo>in_progress,done;..."
+	WorkflowInitial string // status of newly created todos
+
+	// Archive
+	ArchiveAfter time.Duration // completed todos are archived after this long; 0 disables the sweeper
+
+	// Trash
+	TrashRetention time.Duration // how long deleted todos and blog posts can be restored
+
+	// Undo
+	UndoWindow time.Duration // how long an undo token returned by a mutating endpoint stays valid
+
+	// Search ("postgres" or "memory")
+	SearchBackend string
+
+	// Attachment storage ("local" or "s3")
+	StorageBackend  string
+	StorageLocalDir string
+	S3Endpoint      string // e.g. https://s3.ap-northeast-2.amazonaws.com or http://localhost:9000 for MinIO
+	S3Region        string
+	S3Bucket        string
+	S3AccessKey     string
+	S3SecretKey     string
+	S3UsePathStyle  bool // address buckets as endpoint/bucket (required by most S3-compatible servers)
+
+	// Attachment limits
+	AttachmentMaxSize      int64    // bytes
+	AttachmentAllowedTypes []string // MIME types; "image/*" allows every image type
 }
 
 func LoadConfig() *Config {
@@ -49,6 +87,32 @@ func LoadConfig() *Config {
 
 		MongoURL: getEnv("MONGO_URL", "mongodb://localhost:27017"),
 		MongoDB:  getEnv("MONGO_DB", "testbox"),
+
+		SchedulerInterval: time.Duration(getEnvInt("SCHEDULER_INTERVAL_SECONDS", 10)) * time.Second,
+		ReminderLead:      time.Duration(getEnvInt("REMINDER_LEAD_MINUTES", 60)) * time.Minute,
+
+		WorkflowSpec:    getEnv("TODO_WORKFLOW", workflow.DefaultSpec),
+		WorkflowInitial: getEnv("TODO_INITIAL_STATUS", workflow.Todo),
+
+		ArchiveAfter: time.Duration(getEnvInt("TODO_ARCHIVE_AFTER_DAYS", 14)) * 24 * time.Hour,
+
+		TrashRetention: time.Duration(getEnvInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+
+		UndoWindow: time.Duration(getEnvInt("UNDO_WINDOW_SECONDS", 60)) * time.Second,
+
+		SearchBackend: getEnv("SEARCH_BACKEND", "postgres"),
+
+		StorageBackend:  getEnv("STORAGE_BACKEND", "local"),
+		StorageLocalDir: getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+		S3Endpoint:      getEnv("S3_ENDPOINT", "http://localhost:9000"),
+		S3Region:        getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:        getEnv("S3_BUCKET", "testbox"),
+		S3AccessKey:     getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:     getEnv("S3_SECRET_KEY", ""),
+		S3UsePathStyle:  getEnv("S3_USE_PATH_STYLE", "true") == "true",
+
+		AttachmentMaxSize:      int64(getEnvInt("ATTACHMENT_MAX_SIZE_MB", 10)) << 20,
+		AttachmentAllowedTypes: splitList(getEnv("ATTACHMENT_ALLOWED_TYPES", "image/*,application/pdf,text/plain")),
 	}
 }
 
@@ -59,3 +123,29 @@ func getEnv(key, defaultValue string) string {
 	log.Printf("Environment variable %s not set, using default: %s", key, defaultValue)
 	return defaultValue
 }
+
+// splitList splits a comma-separated value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		log.Printf("Environment variable %s not set, using default: %d", key, defaultValue)
+		return defaultValue
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Environment variable %s is not a number (%q), using default: %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
@@ -0,0 +1,172 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"testbox/internal/models"
+	"testbox/internal/slug"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// runMigrations applies data migrations that AutoMigrate cannot express.
+// Every step must be idempotent because it runs on each startup.
+func runMigrations(db *gorm.DB) error {
+	steps := []struct {
+		name string
+		fn   func(*gorm.DB) error
+	}{
+		{"split blog_posts.tags into tags table", migrateBlogTags},
+		{"backfill todo positions", migrateTodoPositions},
+		{"derive todo status from completed", migrateTodoStatus},
+		{"backfill todo completed_at", migrateTodoCompletedAt},
+		{"maintain full-text search vectors", migrateSearch},
+		{"backfill blog post slugs", migrateBlogSlugs},
+		{"backfill blog post published_at", migrateBlogPublishedAt},
+	}
+
+	for _, step := range steps {
+		if err := step.fn(db); err != nil {
+			return fmt.Errorf("%s: %w", step.name, err)
+		}
+	}
+	return nil
+}
+
+// migrateBlogTags moves the legacy comma-separated blog_posts.tags column into
+// the normalized tags / blog_post_tags tables and then drops the column
+func migrateBlogTags(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("blog_posts", "tags") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var rows []struct {
+			ID   string
+			Tags string
+		}
+		if err := tx.Raw("SELECT id, tags FROM blog_posts WHERE tags IS NOT NULL AND tags <> ''").Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		migrated := 0
+		for _, row := range rows {
+			names := models.NormalizeTagNames(strings.Split(row.Tags, ","))
+			for _, name := range names {
+				if err := tx.Exec(
+					"INSERT INTO tags (id, name, created_at) VALUES (?, ?, NOW()) ON CONFLICT (name) DO NOTHING",
+					uuid.New().String(), name,
+				).Error; err != nil {
+					return err
+				}
+				if err := tx.Exec(`
+					INSERT INTO blog_post_tags (blog_post_id, tag_id)
+					SELECT ?, id FROM tags WHERE name = ?
+					ON CONFLICT DO NOTHING`, row.ID, name).Error; err != nil {
+					return err
+				}
+			}
+			migrated++
+		}
+
+		if err := tx.Migrator().DropColumn("blog_posts", "tags"); err != nil {
+			return err
+		}
+
+		log.Printf("✓ Migrated tags of %d blog posts", migrated)
+		return nil
+	})
+}
+
+// migrateTodoPositions gives existing todos manual positions matching the old
+// newest-first order. It only runs while every todo still has the default position.
+func migrateTodoPositions(db *gorm.DB) error {
+	return db.Exec(`
+		UPDATE todos SET position = ranked.rn * 1024
+		FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY created_at DESC) AS rn FROM todos) ranked
+		WHERE todos.id = ranked.id
+			AND NOT EXISTS (SELECT 1 FROM todos WHERE position <> 0)`).Error
+}
+
+// migrateTodoStatus moves todos completed before workflow statuses existed
+// into the "done" status; new columns default every other todo to "todo"
+func migrateTodoStatus(db *gorm.DB) error {
+	result := db.Exec("UPDATE todos SET status = 'done' WHERE completed = true AND status <> 'done'")
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("✓ Moved %d completed todos to the done status", result.RowsAffected)
+	}
+	return nil
+}
+
+// migrateTodoCompletedAt uses updated_at as the completion time of todos that were
+// completed before completed_at existed, so the archive sweeper can pick them up
+func migrateTodoCompletedAt(db *gorm.DB) error {
+	result := db.Exec("UPDATE todos SET completed_at = updated_at WHERE completed = true AND completed_at IS NULL")
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("✓ Backfilled completed_at of %d todos", result.RowsAffected)
+	}
+	return nil
+}
+
+// migrateBlogSlugs gives posts created before slugs existed a unique slug from their
+// title, oldest post first so that it keeps the slug without a suffix
+func migrateBlogSlugs(db *gorm.DB) error {
+	var rows []struct {
+		ID    string
+		Title string
+	}
+	if err := db.Raw("SELECT id, title FROM blog_posts WHERE slug IS NULL OR slug = '' ORDER BY created_at ASC").Scan(&rows).Error; err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var existing []string
+	if err := db.Raw("SELECT slug FROM blog_posts WHERE slug <> '' UNION SELECT slug FROM blog_slugs").Scan(&existing).Error; err != nil {
+		return err
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, s := range existing {
+		taken[s] = true
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			base := slug.Make(row.Title)
+			candidate := base
+			for n := 2; taken[candidate]; n++ {
+				candidate = slug.WithSuffix(base, n)
+			}
+			taken[candidate] = true
+
+			if err := tx.Exec("UPDATE blog_posts SET slug = ? WHERE id = ?", candidate, row.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		log.Printf("✓ Generated slugs for %d blog posts", len(rows))
+		return nil
+	})
+}
+
+// migrateBlogPublishedAt uses created_at as the publication time of posts that were
+// public before blog post statuses existed; AutoMigrate gives them the published status
+func migrateBlogPublishedAt(db *gorm.DB) error {
+	result := db.Exec("UPDATE blog_posts SET published_at = created_at WHERE status = ? AND published_at IS NULL", models.BlogPublished)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("✓ Backfilled published_at of %d blog posts", result.RowsAffected)
+	}
+	return nil
+}
@@ -31,10 +31,20 @@ func NewPostgresDB(cfg *config.Config) (*PostgresDB, error) {
 	log.Println("✓ Connected to PostgreSQL")
 
 	// Auto-migrate models
-	if err := db.AutoMigrate(&models.Todo{}, &models.BlogPost{}); err != nil {
+	if err := db.AutoMigrate(
+		&models.Todo{}, &models.BlogPost{}, &models.ScheduledJob{}, &models.List{}, &models.Tag{},
+		&models.TodoDependency{}, &models.TimeEntry{}, &models.Comment{}, &models.Activity{},
+		&models.Attachment{}, &models.Revision{}, &models.Template{}, &models.SavedFilter{},
+		&models.BlogSlug{},
+	); err != nil {
 		return nil, fmt.Errorf("failed to migrate models: %w", err)
 	}
 
+	// Data migrations that AutoMigrate cannot express
+	if err := runMigrations(db); err != nil {
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
 	log.Println("✓ Database migration completed")
 
 	return &PostgresDB{DB: db}, nil
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"gorm.io/gorm"
+)
+
+// searchStatements add the search_vector columns of todos and blog posts, the functions
+// and triggers that keep them current and their GIN indexes. Titles weigh most (A), then
+// tag names (B), then content (C). The "simple" configuration only lowercases words without
+// stemming, which suits mixed Korean and English text. Tags live in join tables, so changes
+// to todo_tags, blog_post_tags and tag names refresh the vectors of the affected rows as well.
+var searchStatements = []string{
+	`ALTER TABLE todos ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+	`ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+
+	`CREATE OR REPLACE FUNCTION todo_search_vector(todo_id uuid, title text, content text) RETURNS tsvector AS $$
+		SELECT setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce((
+				SELECT string_agg(tags.name, ' ') FROM todo_tags
+				JOIN tags ON tags.id = todo_tags.tag_id
+				WHERE todo_tags.todo_id = $1), '')), 'B') ||
+			setweight(to_tsvector('simple', coalesce(content, '')), 'C')
+	$$ LANGUAGE sql STABLE`,
+	`CREATE OR REPLACE FUNCTION blog_search_vector(post_id uuid, title text, content text) RETURNS tsvector AS $$
+		SELECT setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce((
+				SELECT string_agg(tags.name, ' ') FROM blog_post_tags
+				JOIN tags ON tags.id = blog_post_tags.tag_id
+				WHERE blog_post_tags.blog_post_id = $1), '')), 'B') ||
+			setweight(to_tsvector('simple', coalesce(content, '')), 'C')
+	$$ LANGUAGE sql STABLE`,
+
+	`CREATE OR REPLACE FUNCTION todos_search_trigger() RETURNS trigger AS $$
+	BEGIN
+		NEW.search_vector := todo_search_vector(NEW.id, NEW.title, NEW.content);
+		RETURN NEW;
+	END
+	$$ LANGUAGE plpgsql`,
+	`CREATE OR REPLACE FUNCTION blog_posts_search_trigger() RETURNS trigger AS $$
+	BEGIN
+		NEW.search_vector := blog_search_vector(NEW.id, NEW.title, NEW.content);
+		RETURN NEW;
+	END
+	$$ LANGUAGE plpgsql`,
+	`CREATE OR REPLACE FUNCTION todo_tags_search_trigger() RETURNS trigger AS $$
+	BEGIN
+		IF TG_OP <> 'INSERT' THEN
+			UPDATE todos SET search_vector = todo_search_vector(id, title, content) WHERE id = OLD.todo_id;
+		END IF;
+		IF TG_OP <> 'DELETE' THEN
+			UPDATE todos SET search_vector = todo_search_vector(id, title, content) WHERE id = NEW.todo_id;
+		END IF;
+		RETURN NULL;
+	END
+	$$ LANGUAGE plpgsql`,
+	`CREATE OR REPLACE FUNCTION blog_post_tags_search_trigger() RETURNS trigger AS $$
+	BEGIN
+		IF TG_OP <> 'INSERT' THEN
+			UPDATE blog_posts SET search_vector = blog_search_vector(id, title, content) WHERE id = OLD.blog_post_id;
+		END IF;
+		IF TG_OP <> 'DELETE' THEN
+			UPDATE blog_posts SET search_vector = blog_search_vector(id, title, content) WHERE id = NEW.blog_post_id;
+		END IF;
+		RETURN NULL;
+	END
+	$$ LANGUAGE plpgsql`,
+	`CREATE OR REPLACE FUNCTION tags_search_trigger() RETURNS trigger AS $$
+	BEGIN
+		UPDATE todos SET search_vector = todo_search_vector(id, title, content)
+			WHERE id IN (SELECT todo_id FROM todo_tags WHERE tag_id = NEW.id);
+		UPDATE blog_posts SET search_vector = blog_search_vector(id, title, content)
+			WHERE id IN (SELECT blog_post_id FROM blog_post_tags WHERE tag_id = NEW.id);
+		RETURN NULL;
+	END
+	$$ LANGUAGE plpgsql`,
+
+	`DROP TRIGGER IF EXISTS todos_search ON todos`,
+	`CREATE TRIGGER todos_search BEFORE INSERT OR UPDATE OF title, content ON todos
+		FOR EACH ROW EXECUTE FUNCTION todos_search_trigger()`,
+	`DROP TRIGGER IF EXISTS blog_posts_search ON blog_posts`,
+	`CREATE TRIGGER blog_posts_search BEFORE INSERT OR UPDATE OF title, content ON blog_posts
+		FOR EACH ROW EXECUTE FUNCTION blog_posts_search_trigger()`,
+	`DROP TRIGGER IF EXISTS todo_tags_search ON todo_tags`,
+	`CREATE TRIGGER todo_tags_search AFTER INSERT OR UPDATE OR DELETE ON todo_tags
+		FOR EACH ROW EXECUTE FUNCTION todo_tags_search_trigger()`,
+	`DROP TRIGGER IF EXISTS blog_post_tags_search ON blog_post_tags`,
+	`CREATE TRIGGER blog_post_tags_search AFTER INSERT OR UPDATE OR DELETE ON blog_post_tags
+		FOR EACH ROW EXECUTE FUNCTION blog_post_tags_search_trigger()`,
+	`DROP TRIGGER IF EXISTS tags_search ON tags`,
+	`CREATE TRIGGER tags_search AFTER UPDATE OF name ON tags
+		FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name) EXECUTE FUNCTION tags_search_trigger()`,
+
+	// Rows stored before the triggers existed
+	`UPDATE todos SET search_vector = todo_search_vector(id, title, content) WHERE search_vector IS NULL`,
+	`UPDATE blog_posts SET search_vector = blog_search_vector(id, title, content) WHERE search_vector IS NULL`,
+
+	`CREATE INDEX IF NOT EXISTS idx_todos_search_vector ON todos USING GIN (search_vector)`,
+	`CREATE INDEX IF NOT EXISTS idx_blog_posts_search_vector ON blog_posts USING GIN (search_vector)`,
+}
+
+// migrateSearch creates or refreshes the full-text search columns, triggers and indexes
+func migrateSearch(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range searchStatements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
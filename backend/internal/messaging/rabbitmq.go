@@ -9,6 +9,9 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// eventsExchange fans todo events out to the todo_events queue and to in-process subscribers
+const eventsExchange = "todo_events"
+
 type RabbitMQ struct {
 	conn    *amqp.Connection
 	channel *amqp.Channel
@@ -48,6 +51,19 @@ func NewRabbitMQ(cfg *config.Config) (*RabbitMQ, error) {
 		return nil, fmt.Errorf("failed to declare queue: %w", err)
 	}
 
+	// Events are published to a fanout exchange so that subscribers such as the search
+	// index get their own copy instead of competing with the todo_events consumers
+	if err := channel.ExchangeDeclare(eventsExchange, "fanout", true, false, false, false, nil); err != nil {
+		channel.Close()
+		conn.Close()
+		return nil, fmt.Errorf("failed to declare exchange: %w", err)
+	}
+	if err := channel.QueueBind(queue.Name, "", eventsExchange, false, nil); err != nil {
+		channel.Close()
+		conn.Close()
+		return nil, fmt.Errorf("failed to bind queue: %w", err)
+	}
+
 	log.Println("✓ Connected to RabbitMQ")
 
 	return &RabbitMQ{
@@ -65,13 +81,13 @@ func (r *RabbitMQ) PublishEvent(event TodoEvent) error {
 	}
 
 	err = r.channel.Publish(
-		"",           // exchange
-		r.queue.Name, // routing key
-		false,        // mandatory
-		false,        // immediate
+		eventsExchange, // exchange
+		r.queue.Name,   // routing key
+		false,          // mandatory
+		false,          // immediate
 		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
+			ContentType:  "application/json",
+			Body:         body,
 			DeliveryMode: amqp.Persistent,
 		},
 	)
@@ -83,6 +99,46 @@ func (r *RabbitMQ) PublishEvent(event TodoEvent) error {
 	return nil
 }
 
+// Subscribe delivers every event published from now on to handler, in order, on its own
+// goroutine. Each subscriber gets a private queue that is removed when the connection closes.
+func (r *RabbitMQ) Subscribe(name string, handler func(TodoEvent)) error {
+	channel, err := r.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	queue, err := channel.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		channel.Close()
+		return fmt.Errorf("failed to declare queue: %w", err)
+	}
+	if err := channel.QueueBind(queue.Name, "", eventsExchange, false, nil); err != nil {
+		channel.Close()
+		return fmt.Errorf("failed to bind queue: %w", err)
+	}
+
+	deliveries, err := channel.Consume(queue.Name, name, true, true, false, false, nil)
+	if err != nil {
+		channel.Close()
+		return fmt.Errorf("failed to consume: %w", err)
+	}
+
+	go func() {
+		for delivery := range deliveries {
+			var event TodoEvent
+			if err := json.Unmarshal(delivery.Body, &event); err != nil {
+				log.Printf("경고: 이벤트 해석 실패 (%s): %v", name, err)
+				continue
+			}
+			handler(event)
+		}
+		log.Printf("이벤트 구독 종료: %s", name)
+	}()
+
+	log.Printf("✓ Subscribed to events: %s", name)
+	return nil
+}
+
 func (r *RabbitMQ) Close() error {
 	if r.channel != nil {
 		r.channel.Close()
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Attachment owner types
+const (
+	AttachmentOwnerTodo = "todo"
+	AttachmentOwnerBlog = "blog_post"
+)
+
+// Attachment is an uploaded file belonging to a todo or a blog post.
+// The file itself lives in the configured storage backend under StorageKey.
+type Attachment struct {
+	ID          string    `gorm:"primaryKey;type:uuid" json:"id"`
+	OwnerType   string    `gorm:"type:varchar(20);not null;index:idx_attachments_owner" json:"owner_type"`
+	OwnerID     string    `gorm:"type:uuid;not null;index:idx_attachments_owner" json:"owner_id"`
+	FileName    string    `gorm:"type:varchar(255);not null" json:"file_name"`
+	ContentType string    `gorm:"type:varchar(100);not null" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	StorageKey  string    `gorm:"type:varchar(500);not null" json:"-"`
+	UploadedBy  string    `gorm:"type:varchar(100)" json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// BeforeCreate hook to generate UUID
+func (a *Attachment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
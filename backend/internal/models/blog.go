@@ -7,14 +7,47 @@ import (
 	"gorm.io/gorm"
 )
 
+// Blog post statuses
+const (
+	BlogDraft     = "draft"     // only visible to identified users
+	BlogScheduled = "scheduled" // published automatically at PublishedAt
+	BlogPublished = "published" // listed for everyone
+	BlogUnlisted  = "unlisted"  // reachable by ID or slug but not listed
+)
+
 // BlogPost represents a blog post for development journal
 type BlogPost struct {
-	ID        string    `gorm:"primaryKey;type:uuid" json:"id"`
-	Title     string    `gorm:"type:varchar(255);not null" json:"title"`
-	Content   string    `gorm:"type:text;not null" json:"content"`
-	Tags      string    `gorm:"type:varchar(500)" json:"tags"` // Comma-separated tags
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string         `gorm:"primaryKey;type:uuid" json:"id"`
+	Title       string         `gorm:"type:varchar(255);not null" json:"title"`
+	Slug        string         `gorm:"type:varchar(255);uniqueIndex" json:"slug"` // URL name derived from the title
+	Content     string         `gorm:"type:text;not null" json:"content"`
+	Status      string         `gorm:"type:varchar(20);not null;default:published;index" json:"status"`
+	PublishedAt *time.Time     `gorm:"index" json:"published_at"` // planned time while scheduled
+	Tags        []Tag          `gorm:"many2many:blog_post_tags" json:"tags"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"` // set while the post is in the trash
+}
+
+// IsValidBlogStatus reports whether status is one of the blog post statuses
+func IsValidBlogStatus(status string) bool {
+	switch status {
+	case BlogDraft, BlogScheduled, BlogPublished, BlogUnlisted:
+		return true
+	}
+	return false
+}
+
+// IsPublic reports whether anonymous readers may open the post
+func (b *BlogPost) IsPublic() bool {
+	return b.Status == BlogPublished || b.Status == BlogUnlisted
+}
+
+// BlogSlug is a former slug of a blog post, kept so that old URLs redirect to the post
+type BlogSlug struct {
+	Slug       string    `gorm:"primaryKey;type:varchar(255)" json:"slug"`
+	BlogPostID string    `gorm:"type:uuid;not null;index" json:"blog_post_id"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 // BeforeCreate hook to generate UUID
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Timeline item types
+const (
+	TimelineComment  = "comment"
+	TimelineActivity = "activity"
+)
+
+// Comment is a message in a todo's discussion thread
+type Comment struct {
+	ID        string    `gorm:"primaryKey;type:uuid" json:"id"`
+	TodoID    string    `gorm:"type:uuid;not null;index" json:"todo_id"`
+	Author    string    `gorm:"type:varchar(100);not null" json:"author"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	Edited    bool      `gorm:"default:false" json:"edited"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Activity is a recorded change to a todo, such as a status change or a move
+type Activity struct {
+	ID        string    `gorm:"primaryKey;type:uuid" json:"id"`
+	TodoID    string    `gorm:"type:uuid;not null;index" json:"todo_id"`
+	Actor     string    `gorm:"type:varchar(100)" json:"actor"` // empty for changes made by the system
+	Action    string    `gorm:"type:varchar(50);not null" json:"action"`
+	Data      string    `gorm:"type:text" json:"-"` // JSON encoded details, e.g. {"from":"todo","to":"done"}
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TimelineItem is a comment or an activity in a todo's merged timeline
+type TimelineItem struct {
+	Type    string          `json:"type"` // "comment" or "activity"
+	At      time.Time       `json:"at"`
+	Actor   string          `json:"actor"`
+	Comment *Comment        `json:"comment,omitempty"`
+	Action  string          `json:"action,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// BeforeCreate hook to generate UUID
+func (c *Comment) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// BeforeCreate hook to generate UUID
+func (a *Activity) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// TodoDependency records that TodoID cannot be completed until BlockerID is done
+type TodoDependency struct {
+	TodoID    string    `gorm:"primaryKey;type:uuid" json:"todo_id"`
+	BlockerID string    `gorm:"primaryKey;type:uuid;index" json:"blocker_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TodoDetail is a todo together with the todos it depends on and the todos depending on it
+type TodoDetail struct {
+	Todo
+	Blockers   []Todo `json:"blockers"`
+	Dependents []Todo `json:"dependents"`
+}
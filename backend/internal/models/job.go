@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Job statuses
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// ScheduledJob is a one-off or cron-style background job persisted in PostgreSQL
+type ScheduledJob struct {
+	ID          string     `gorm:"primaryKey;type:uuid" json:"id"`
+	Key         string     `gorm:"type:varchar(255);uniqueIndex;not null" json:"key"` // e.g. "todo_reminder:<todo id>"
+	Kind        string     `gorm:"type:varchar(100);index;not null" json:"kind"`
+	Payload     string     `gorm:"type:text" json:"payload"`           // JSON encoded
+	CronSpec    string     `gorm:"type:varchar(100)" json:"cron_spec"` // empty for one-off jobs
+	RunAt       time.Time  `gorm:"index;not null" json:"run_at"`
+	Status      string     `gorm:"type:varchar(20);index;default:pending" json:"status"`
+	Attempts    int        `gorm:"default:0" json:"attempts"`
+	LastError   string     `gorm:"type:text" json:"last_error"`
+	LockedUntil *time.Time `json:"locked_until"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// BeforeCreate hook to generate UUID
+func (j *ScheduledJob) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == "" {
+		j.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// List groups todos into a project or context
+type List struct {
+	ID          string    `gorm:"primaryKey;type:uuid" json:"id"`
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	Color       string    `gorm:"type:varchar(20)" json:"color"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ListCounts holds the number of open and done todos in a list
+type ListCounts struct {
+	OpenCount int64 `json:"open_count"`
+	DoneCount int64 `json:"done_count"`
+}
+
+// ListSummary is a list together with its todo counts
+type ListSummary struct {
+	List
+	ListCounts
+}
+
+// BeforeCreate hook to generate UUID
+func (l *List) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	return nil
+}
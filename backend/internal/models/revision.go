@@ -0,0 +1,187 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Revision owner types
+const (
+	RevisionOwnerTodo = "todo"
+	RevisionOwnerBlog = "blog_post"
+)
+
+// Revision actions
+const (
+	RevisionInitial  = "initial" // state before the first recorded change of an item created without history
+	RevisionCreated  = "created"
+	RevisionUpdated  = "updated"
+	RevisionReverted = "reverted"
+	RevisionUndone   = "undone" // restored by an undo token; status transition rules do not apply
+)
+
+// Revision is a snapshot of a todo's or blog post's editable fields taken after a change
+type Revision struct {
+	ID            string    `gorm:"primaryKey;type:uuid" json:"id"`
+	OwnerType     string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_revisions_owner_number" json:"owner_type"`
+	OwnerID       string    `gorm:"type:uuid;not null;uniqueIndex:idx_revisions_owner_number" json:"owner_id"`
+	Number        int       `gorm:"not null;uniqueIndex:idx_revisions_owner_number" json:"number"` // 1-based per owner
+	Action        string    `gorm:"type:varchar(20);not null" json:"action"`
+	Author        string    `gorm:"type:varchar(100)" json:"author"` // empty when unknown or changed by the system
+	ChangedFields string    `gorm:"type:text" json:"-"`              // comma-separated snapshot fields changed by this revision
+	Snapshot      string    `gorm:"type:text;not null" json:"-"`     // JSON encoded TodoSnapshot or BlogSnapshot
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// RevisionView is a revision with its decoded snapshot, as returned by the API
+type RevisionView struct {
+	Revision
+	Fields   []string        `json:"fields"`
+	Snapshot json.RawMessage `json:"snapshot"`
+}
+
+// FieldChange is a field whose value differs between two revisions
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+// RevisionDiff lists the fields that changed between two revisions
+type RevisionDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// TodoSnapshot is the revisioned part of a todo
+type TodoSnapshot struct {
+	Title      string     `json:"title"`
+	Content    string     `json:"content"`
+	Status     string     `json:"status"`
+	Priority   int        `json:"priority"`
+	StartDate  *time.Time `json:"start_date"`
+	DueDate    *time.Time `json:"due_date"`
+	ReminderAt *time.Time `json:"reminder_at"`
+	Recurrence string     `json:"recurrence"`
+	Tags       []string   `json:"tags"`
+}
+
+// BlogSnapshot is the revisioned part of a blog post
+type BlogSnapshot struct {
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
+}
+
+// NewTodoSnapshot captures the revisioned fields of a todo
+func NewTodoSnapshot(todo *Todo) TodoSnapshot {
+	return TodoSnapshot{
+		Title:      todo.Title,
+		Content:    todo.Content,
+		Status:     todo.Status,
+		Priority:   todo.Priority,
+		StartDate:  snapshotTime(todo.StartDate),
+		DueDate:    snapshotTime(todo.DueDate),
+		ReminderAt: snapshotTime(todo.ReminderAt),
+		Recurrence: todo.Recurrence,
+		Tags:       snapshotTagNames(todo.Tags),
+	}
+}
+
+// NewBlogSnapshot captures the revisioned fields of a blog post
+func NewBlogSnapshot(blog *BlogPost) BlogSnapshot {
+	return BlogSnapshot{
+		Title:   blog.Title,
+		Content: blog.Content,
+		Tags:    snapshotTagNames(blog.Tags),
+	}
+}
+
+// snapshotTime normalizes a time to UTC with database precision so equal instants encode identically
+func snapshotTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC().Truncate(time.Microsecond)
+	return &u
+}
+
+// snapshotTagNames returns sorted tag names so equal tag sets encode identically
+func snapshotTagNames(tags []Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DiffSnapshots compares two JSON encoded snapshots field by field, in field name order.
+// An empty from snapshot reports every field of to as changed.
+func DiffSnapshots(from, to string) ([]FieldChange, error) {
+	before := map[string]json.RawMessage{}
+	if from != "" {
+		if err := json.Unmarshal([]byte(from), &before); err != nil {
+			return nil, err
+		}
+	}
+	after := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(to), &after); err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(after))
+	for field := range after {
+		fields = append(fields, field)
+	}
+	for field := range before {
+		if _, ok := after[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := []FieldChange{}
+	for _, field := range fields {
+		if bytes.Equal(before[field], after[field]) {
+			continue
+		}
+		changes = append(changes, FieldChange{
+			Field: field,
+			From:  jsonOrNull(before[field]),
+			To:    jsonOrNull(after[field]),
+		})
+	}
+	return changes, nil
+}
+
+// View decodes the revision for API responses
+func (r *Revision) View() RevisionView {
+	fields := []string{}
+	if r.ChangedFields != "" {
+		fields = strings.Split(r.ChangedFields, ",")
+	}
+	return RevisionView{Revision: *r, Fields: fields, Snapshot: json.RawMessage(r.Snapshot)}
+}
+
+func jsonOrNull(v json.RawMessage) json.RawMessage {
+	if v == nil {
+		return json.RawMessage("null")
+	}
+	return v
+}
+
+// BeforeCreate hook to generate UUID
+func (r *Revision) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Search result types
+const (
+	SearchTypeTodo = "todo"
+	SearchTypeBlog = "blog"
+)
+
+// SearchResult is a todo or blog post matching a full-text search.
+// Title and Snippet are HTML-escaped with the matched words wrapped in <mark> tags.
+type SearchResult struct {
+	Type      string    `json:"type"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Snippet   string    `json:"snippet"` // fragments of the content around the matches
+	Rank      float64   `json:"rank"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
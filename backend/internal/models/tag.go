@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tag is a label shared by todos and blog posts
+type Tag struct {
+	ID        string    `gorm:"primaryKey;type:uuid" json:"id"`
+	Name      string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagUsage is a tag together with how many todos and blog posts use it
+type TagUsage struct {
+	Tag
+	TodoCount int64 `json:"todo_count"`
+	BlogCount int64 `json:"blog_count"`
+}
+
+// BeforeCreate hook to generate UUID
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// NormalizeTagName trims a tag, strips a leading '#', collapses inner
+// whitespace and lowercases it so "Go", " go " and "#go" are the same tag
+func NormalizeTagName(name string) string {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "#")
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// NormalizeTagNames normalizes names and drops empty entries and duplicates
+func NormalizeTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name = NormalizeTagName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TemplateVariable matches a {{name}} placeholder in template titles and content
+var TemplateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// Template is a reusable todo with its subtasks, such as an onboarding or release checklist
+type Template struct {
+	ID          string       `gorm:"primaryKey;type:uuid" json:"id"`
+	Name        string       `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"`
+	Description string       `gorm:"type:text" json:"description"`
+	Body        string       `gorm:"type:text;not null" json:"-"` // JSON encoded Todo
+	Todo        TemplateItem `gorm:"-" json:"todo"`
+	Variables   []string     `gorm:"-" json:"variables"` // placeholders used by the items, sorted
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// TemplateItem is a todo of a template together with its subtasks
+type TemplateItem struct {
+	Title         string         `json:"title"`
+	Content       string         `json:"content"`
+	Priority      int            `json:"priority"`
+	DueOffsetDays *int           `json:"due_offset_days"` // due date in days after the start date; nil for none
+	Tags          []string       `json:"tags"`
+	Subtasks      []TemplateItem `json:"subtasks"`
+}
+
+// Walk calls fn for the item and all of its subtasks, parents first
+func (item *TemplateItem) Walk(fn func(item *TemplateItem)) {
+	fn(item)
+	for i := range item.Subtasks {
+		item.Subtasks[i].Walk(fn)
+	}
+}
+
+// BeforeCreate hook to generate UUID
+func (t *Template) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// BeforeSave encodes the item tree into Body
+func (t *Template) BeforeSave(tx *gorm.DB) error {
+	body, err := json.Marshal(t.Todo)
+	if err != nil {
+		return err
+	}
+	t.Body = string(body)
+	t.Variables = t.usedVariables()
+	return nil
+}
+
+// AfterFind decodes Body into the item tree
+func (t *Template) AfterFind(tx *gorm.DB) error {
+	if err := json.Unmarshal([]byte(t.Body), &t.Todo); err != nil {
+		return err
+	}
+	t.Variables = t.usedVariables()
+	return nil
+}
+
+func (t *Template) usedVariables() []string {
+	seen := map[string]bool{}
+	variables := []string{}
+	t.Todo.Walk(func(item *TemplateItem) {
+		for _, text := range []string{item.Title, item.Content} {
+			for _, match := range TemplateVariable.FindAllStringSubmatch(text, -1) {
+				if !seen[match[1]] {
+					seen[match[1]] = true
+					variables = append(variables, match[1])
+				}
+			}
+		}
+	})
+	sort.Strings(variables)
+	return variables
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TimeEntry is a span of time a user spent on a todo. A running timer has no EndedAt;
+// the partial unique index allows at most one running timer per user.
+type TimeEntry struct {
+	ID        string     `gorm:"primaryKey;type:uuid" json:"id"`
+	TodoID    string     `gorm:"type:uuid;not null;index" json:"todo_id"`
+	UserID    string     `gorm:"type:varchar(100);not null;index;uniqueIndex:idx_time_entries_running,where:ended_at IS NULL" json:"user_id"`
+	StartedAt time.Time  `gorm:"not null;index" json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Duration  int64      `gorm:"default:0" json:"duration_seconds"` // set when the entry is finished
+	Note      string     `gorm:"type:text" json:"note"`
+	Manual    bool       `gorm:"default:false" json:"manual"` // entered by hand rather than timed
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TimeReportRow is the tracked time of one group in a time report
+type TimeReportRow struct {
+	Key     string `json:"key"`   // todo ID, list ID or YYYY-MM-DD depending on the grouping
+	Label   string `json:"label"` // todo title or list name (empty for days and todos without a list)
+	Seconds int64  `json:"seconds"`
+	Entries int64  `json:"entries"`
+}
+
+// BeforeCreate hook to generate UUID
+func (e *TimeEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Finish stops the entry at the given time and records its duration
+func (e *TimeEntry) Finish(at time.Time) {
+	e.EndedAt = &at
+	e.Duration = int64(at.Sub(e.StartedAt).Seconds())
+}
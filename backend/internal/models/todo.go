@@ -7,14 +7,52 @@ import (
 	"gorm.io/gorm"
 )
 
+// Priority levels for todos (higher value means more urgent)
+const (
+	PriorityNone   = 0
+	PriorityLow    = 1
+	PriorityMedium = 2
+	PriorityHigh   = 3
+	PriorityUrgent = 4
+)
+
 // Todo represents a todo item with title and content
 type Todo struct {
-	ID        string    `gorm:"primaryKey;type:uuid" json:"id"`
-	Title     string    `gorm:"type:varchar(255);not null" json:"title"`
-	Content   string    `gorm:"type:text" json:"content"`
-	Completed bool      `gorm:"default:false" json:"completed"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string         `gorm:"primaryKey;type:uuid" json:"id"`
+	Title       string         `gorm:"type:varchar(255);not null" json:"title"`
+	Content     string         `gorm:"type:text" json:"content"`
+	Status      string         `gorm:"type:varchar(32);not null;default:'todo';index" json:"status"` // workflow status
+	Completed   bool           `gorm:"default:false" json:"completed"`                               // derived: Status == "done"
+	ParentID    *string        `gorm:"type:uuid;index" json:"parent_id"`
+	ListID      *string        `gorm:"type:uuid;index" json:"list_id"`
+	Priority    int            `gorm:"default:0;index" json:"priority"`
+	Position    float64        `gorm:"default:0;index" json:"position"` // manual order, ascending
+	StartDate   *time.Time     `json:"start_date"`
+	DueDate     *time.Time     `gorm:"index" json:"due_date"`
+	ReminderAt  *time.Time     `json:"reminder_at"`
+	Recurrence  string         `gorm:"type:varchar(255)" json:"recurrence"` // RRULE, e.g. "FREQ=WEEKLY;BYDAY=MO"
+	SeriesID    *string        `gorm:"type:uuid;index" json:"series_id"`    // first occurrence of a recurring series
+	Occurrence  int            `gorm:"default:1" json:"occurrence"`         // 1-based index within the series
+	CompletedAt *time.Time     `gorm:"index" json:"completed_at"`           // when the todo last reached "done"
+	ArchivedAt  *time.Time     `gorm:"index" json:"archived_at"`            // set while the todo is archived
+	Tags        []Tag          `gorm:"many2many:todo_tags" json:"tags"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"` // set while the todo is in the trash
+}
+
+// TodoNode is a todo together with its nested subtasks
+type TodoNode struct {
+	Todo
+	Children []*TodoNode `json:"children"`
+}
+
+// BoardColumn is one status column of a board view
+type BoardColumn struct {
+	Status      string   `json:"status"`
+	Transitions []string `json:"transitions"` // statuses reachable from this column
+	Count       int      `json:"count"`
+	Items       []Todo   `json:"items"`
 }
 
 // BeforeCreate hook to generate UUID
@@ -24,3 +62,28 @@ func (t *Todo) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// IsValidPriority reports whether p is one of the defined priority levels
+func IsValidPriority(p int) bool {
+	return p >= PriorityNone && p <= PriorityUrgent
+}
+
+// BuildTodoTree arranges a flat subtree (as returned by a recursive query)
+// into nested nodes rooted at rootID. Returns nil if rootID is not present.
+func BuildTodoTree(rootID string, todos []Todo) *TodoNode {
+	nodes := make(map[string]*TodoNode, len(todos))
+	for i := range todos {
+		nodes[todos[i].ID] = &TodoNode{Todo: todos[i], Children: []*TodoNode{}}
+	}
+
+	for _, todo := range todos {
+		if todo.ID == rootID || todo.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*todo.ParentID]; ok {
+			parent.Children = append(parent.Children, nodes[todo.ID])
+		}
+	}
+
+	return nodes[rootID]
+}
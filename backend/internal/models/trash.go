@@ -0,0 +1,8 @@
+package models
+
+// Trash lists the soft-deleted todos and blog posts that can still be restored.
+// Todos deleted together with their parent are not listed separately.
+type Trash struct {
+	Todos     []Todo     `json:"todos"`
+	BlogPosts []BlogPost `json:"blog_posts"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// View kinds
+const (
+	ViewSmart = "smart" // defined by the server, e.g. Today or Overdue
+	ViewSaved = "saved" // a SavedFilter created by a user
+)
+
+// TodoFilter is the stored form of a todo query. Date windows are relative and resolved
+// when the filter is run, so a filter for "today" keeps meaning today.
+type TodoFilter struct {
+	Due       string   `json:"due,omitempty"`         // "overdue", "today" or "week"
+	DueInDays *int     `json:"due_in_days,omitempty"` // due between the start of today and N days later
+	Priority  *int     `json:"priority,omitempty"`
+	Completed *bool    `json:"completed,omitempty"`
+	Status    string   `json:"status,omitempty"`
+	ListID    *string  `json:"list_id,omitempty"` // "" selects todos without a list
+	Tags      []string `json:"tags,omitempty"`    // todos must carry all of them
+	RootOnly  bool     `json:"root_only,omitempty"`
+	Search    string   `json:"q,omitempty"`
+	Archived  string   `json:"archived,omitempty"` // "", "include" or "only"
+	SortBy    string   `json:"sort,omitempty"`
+	Order     string   `json:"order,omitempty"`
+}
+
+// SavedFilter is a named TodoFilter of a user
+type SavedFilter struct {
+	ID        string     `gorm:"primaryKey;type:uuid" json:"id"`
+	UserID    string     `gorm:"type:varchar(100);not null;index" json:"user_id"`
+	Name      string     `gorm:"type:varchar(100);not null" json:"name"`
+	Query     string     `gorm:"type:text;not null" json:"-"` // JSON encoded Filter
+	Filter    TodoFilter `gorm:"-" json:"filter"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// View is a smart view or saved filter with the number of todos it currently shows
+type View struct {
+	ID     string      `json:"id"` // smart view key or saved filter ID
+	Name   string      `json:"name"`
+	Kind   string      `json:"kind"`
+	Count  int64       `json:"count"`
+	Filter *TodoFilter `json:"filter,omitempty"` // saved filters only
+}
+
+// BeforeCreate hook to generate UUID
+func (f *SavedFilter) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == "" {
+		f.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// BeforeSave encodes Filter into Query
+func (f *SavedFilter) BeforeSave(tx *gorm.DB) error {
+	query, err := json.Marshal(f.Filter)
+	if err != nil {
+		return err
+	}
+	f.Query = string(query)
+	return nil
+}
+
+// AfterFind decodes Query into Filter
+func (f *SavedFilter) AfterFind(tx *gorm.DB) error {
+	return json.Unmarshal([]byte(f.Query), &f.Filter)
+}
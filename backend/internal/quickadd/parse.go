@@ -0,0 +1,642 @@
+// Package quickadd turns a one-line todo such as "Pay rent every month on the 1st #finance !high"
+// into structured fields. Recognised phrases are removed from the title:
+//
+//	#tag            tag (repeatable)
+//	!high           priority: !low, !medium, !high, !urgent or !0-!4
+//	@list           list name
+//	every ...       recurrence: every day, every 2 weeks, every monday and friday,
+//	                every weekday, every month on the 1st, daily, weekly, monthly, ...
+//	today, tomorrow, next friday, in 3 days, on the 15th, mar 15, 2025-03-15, 3/15
+//	at 5pm, 17:30   time of day
+//
+// Korean shortcuts (오늘, 내일, 모레, 매일, 매주, 매월, 월요일 ...) are understood as well.
+package quickadd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testbox/internal/models"
+	"testbox/internal/recurrence"
+	"time"
+)
+
+// Result holds the fields extracted from a quick-add line
+type Result struct {
+	Title      string     `json:"title"`
+	DueDate    *time.Time `json:"due_date"`   // end of day (23:59) when only a date is given
+	Recurrence string     `json:"recurrence"` // RRULE accepted by the recurrence package, "" if none
+	Tags       []string   `json:"tags"`       // normalized tag names
+	Priority   int        `json:"priority"`
+	List       string     `json:"list"` // list name without "@", "" if none
+}
+
+// Parse extracts the fields of text. Relative dates are resolved against now, in now's location.
+func Parse(text string, now time.Time) Result {
+	p := &parser{words: strings.Fields(text), now: now}
+	p.lower = make([]string, len(p.words))
+	for i, word := range p.words {
+		p.lower[i] = strings.ToLower(strings.TrimRight(word, ",.;"))
+	}
+	p.used = make([]bool, len(p.words))
+	p.parse()
+	return p.result()
+}
+
+type parser struct {
+	words []string // original words, used for the title
+	lower []string // lowercased words without trailing punctuation, used for matching
+	used  []bool
+	now   time.Time
+
+	tags       []string
+	priority   *int
+	list       string
+	recurrence *rule
+	date       *time.Time // local midnight of the due day
+	hour, min  int
+	hasClock   bool
+	tonight    bool // "tonight" defaults the time to 20:00
+}
+
+// rule is a recurrence before it is formatted as RRULE
+type rule struct {
+	freq     string
+	interval int
+	weekdays []time.Weekday
+	monthDay int
+}
+
+func (p *parser) parse() {
+	for i := 0; i < len(p.words); {
+		n := p.match(i)
+		if n == 0 {
+			i++
+			continue
+		}
+		for j := i; j < i+n; j++ {
+			p.used[j] = true
+		}
+		i += n
+	}
+}
+
+// match tries every phrase at position i and returns how many words it consumed
+func (p *parser) match(i int) int {
+	word := p.words[i]
+	switch {
+	case len(word) > 1 && word[0] == '#':
+		p.tags = append(p.tags, strings.TrimRight(word[1:], ",.;"))
+		return 1
+	case len(word) > 1 && word[0] == '@' && p.list == "":
+		p.list = strings.TrimRight(word[1:], ",.;")
+		return 1
+	case len(word) > 1 && word[0] == '!' && p.priority == nil:
+		if level, ok := priorities[p.lower[i][1:]]; ok {
+			p.priority = &level
+			return 1
+		}
+		return 0
+	}
+
+	if p.recurrence == nil {
+		if n, r := p.matchRecurrence(i); n > 0 {
+			p.recurrence = r
+			return n
+		}
+	}
+	if p.date == nil {
+		if n, date := p.matchDate(i); n > 0 {
+			p.date = &date
+			return n
+		}
+	}
+	if !p.hasClock {
+		if n, hour, min := p.matchClock(i); n > 0 {
+			p.hour, p.min, p.hasClock = hour, min, true
+			return n
+		}
+	}
+	return 0
+}
+
+// word returns the normalized word at i, or "" past the end
+func (p *parser) word(i int) string {
+	if i < 0 || i >= len(p.lower) {
+		return ""
+	}
+	return p.lower[i]
+}
+
+func (p *parser) today() time.Time {
+	return time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+}
+
+// matchRecurrence reads "every ..." phrases and the single-word shortcuts
+func (p *parser) matchRecurrence(i int) (int, *rule) {
+	if freq, ok := shortcutFrequencies[p.word(i)]; ok {
+		return 1, &rule{freq: freq.freq, interval: freq.interval}
+	}
+	if p.word(i) != "every" && p.word(i) != "each" {
+		return 0, nil
+	}
+
+	j := i + 1
+	interval := 1
+	if p.word(j) == "other" {
+		interval, j = 2, j+1
+	} else if n, err := strconv.Atoi(p.word(j)); err == nil && n > 0 {
+		interval, j = n, j+1
+	}
+
+	// every 2 weeks [on monday], every month [on the 1st]
+	if unit, ok := units[p.word(j)]; ok {
+		r := &rule{freq: unit.freq, interval: interval * unit.interval}
+		j++
+		if p.word(j) == "on" {
+			if n, days := p.matchWeekdays(j + 1); n > 0 && r.freq == "WEEKLY" {
+				r.weekdays = days
+				j += 1 + n
+			} else if n, day := p.matchMonthDay(j + 1); n > 0 && r.freq == "MONTHLY" {
+				r.monthDay = day
+				j += 1 + n
+			}
+		}
+		return j - i, r
+	}
+
+	// every weekday, every weekend
+	switch p.word(j) {
+	case "weekday", "weekdays":
+		return j + 1 - i, &rule{freq: "WEEKLY", interval: interval, weekdays: []time.Weekday{
+			time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+		}}
+	case "weekend", "weekends":
+		return j + 1 - i, &rule{freq: "WEEKLY", interval: interval, weekdays: []time.Weekday{time.Saturday, time.Sunday}}
+	}
+
+	// every monday and thursday
+	if n, days := p.matchWeekdays(j); n > 0 {
+		return j + n - i, &rule{freq: "WEEKLY", interval: interval, weekdays: days}
+	}
+
+	// every 1st (of the month)
+	if n, day := p.matchMonthDay(j); n > 0 {
+		j += n
+		if p.word(j) == "of" && p.word(j+1) == "the" && p.word(j+2) == "month" {
+			j += 3
+		}
+		return j - i, &rule{freq: "MONTHLY", interval: interval, monthDay: day}
+	}
+	return 0, nil
+}
+
+// matchWeekdays reads a list such as "mon, wed and fri"
+func (p *parser) matchWeekdays(i int) (int, []time.Weekday) {
+	var days []time.Weekday
+	j := i
+	for {
+		day, ok := weekday(p.word(j))
+		if !ok {
+			break
+		}
+		days = append(days, day)
+		j++
+		if p.word(j) == "and" {
+			if _, ok := weekday(p.word(j + 1)); ok {
+				j++
+			}
+		}
+	}
+	return j - i, days
+}
+
+// matchMonthDay reads "the 1st", "1st", "the last day" or "15th"
+func (p *parser) matchMonthDay(i int) (int, int) {
+	j := i
+	if p.word(j) == "the" {
+		j++
+	}
+	if p.word(j) == "last" {
+		if p.word(j+1) == "day" {
+			return j + 2 - i, -1
+		}
+		return 0, 0
+	}
+	if day, ok := ordinal(p.word(j)); ok {
+		return j + 1 - i, day
+	}
+	return 0, 0
+}
+
+// matchDate reads a due date, optionally introduced by "on", "by" or "due"
+func (p *parser) matchDate(i int) (int, time.Time) {
+	switch p.word(i) {
+	case "on", "by", "due":
+		if n, date := p.matchDate(i + 1); n > 0 {
+			return n + 1, date
+		}
+		if p.word(i) == "on" {
+			if n, day := p.matchMonthDay(i + 1); n > 0 && p.word(i+1) == "the" {
+				return n + 1, p.nextMonthDay(day)
+			}
+		}
+		return 0, time.Time{}
+	}
+
+	today := p.today()
+	word := p.word(i)
+	if days, ok := relativeDays[word]; ok {
+		p.tonight = word == "tonight"
+		return 1, today.AddDate(0, 0, days)
+	}
+
+	switch word {
+	case "next":
+		next := p.word(i + 1)
+		if next == "week" {
+			return 2, startOfWeek(today).AddDate(0, 0, 7)
+		}
+		if next == "month" {
+			return 2, time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location())
+		}
+		if day, ok := weekday(next); ok {
+			return 2, startOfWeek(today).AddDate(0, 0, 7+weekdayIndex(day))
+		}
+	case "다음주":
+		return 1, startOfWeek(today).AddDate(0, 0, 7)
+	case "this":
+		if day, ok := weekday(p.word(i + 1)); ok {
+			return 2, nextWeekday(today, day)
+		}
+	case "in":
+		n, err := strconv.Atoi(p.word(i + 1))
+		if p.word(i+1) == "a" || p.word(i+1) == "an" {
+			n, err = 1, nil
+		}
+		if err == nil && n > 0 {
+			switch strings.TrimSuffix(p.word(i+2), "s") {
+			case "day":
+				return 3, today.AddDate(0, 0, n)
+			case "week":
+				return 3, today.AddDate(0, 0, 7*n)
+			case "month":
+				return 3, today.AddDate(0, n, 0)
+			}
+		}
+	}
+
+	// 단독 요일은 "sun", "sat" 같은 일반 단어와 겹치지 않도록 전체 이름만 인식합니다
+	if day, ok := weekdays[word]; ok && len(word) > 3 {
+		return 1, nextWeekday(today, day)
+	}
+
+	// 2025-03-15
+	if t, err := time.ParseInLocation("2006-01-02", word, today.Location()); err == nil {
+		return 1, t
+	}
+
+	// 3/15, 3/15/2025
+	if month, day, year, ok := slashDate(word); ok {
+		return 1, p.calendarDate(year, month, day)
+	}
+
+	// mar 15, march 15th 2025
+	if month, ok := months[word]; ok {
+		if day, ok := ordinal(p.word(i + 1)); ok && day > 0 {
+			if year, err := strconv.Atoi(p.word(i + 2)); err == nil && year >= 1000 {
+				return 3, time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+			}
+			return 2, p.calendarDate(0, month, day)
+		}
+	}
+
+	// 15 mar, 15th of march
+	if day, ok := ordinal(word); ok && day > 0 {
+		j := i + 1
+		if p.word(j) == "of" {
+			j++
+		}
+		if month, ok := months[p.word(j)]; ok {
+			return j + 1 - i, p.calendarDate(0, month, day)
+		}
+	}
+	return 0, time.Time{}
+}
+
+// matchClock reads "at 5pm", "5:30pm", "17:00", "at 5 pm" or "at noon"
+func (p *parser) matchClock(i int) (int, int, int) {
+	if p.word(i) == "at" {
+		if n, hour, min := p.matchClock(i + 1); n > 0 {
+			return n + 1, hour, min
+		}
+		switch p.word(i + 1) {
+		case "noon":
+			return 2, 12, 0
+		case "midnight":
+			return 2, 0, 0
+		}
+		if hour, err := strconv.Atoi(p.word(i + 1)); err == nil && hour >= 0 && hour < 24 {
+			return 2, hour, 0
+		}
+		return 0, 0, 0
+	}
+
+	word := p.word(i)
+	if suffix := p.word(i + 1); suffix == "am" || suffix == "pm" {
+		if hour, min, ok := clock(word + suffix); ok {
+			return 2, hour, min
+		}
+	}
+	if hour, min, ok := clock(word); ok {
+		return 1, hour, min
+	}
+	return 0, 0, 0
+}
+
+func (p *parser) result() Result {
+	res := Result{Tags: models.NormalizeTagNames(p.tags), List: p.list}
+	if p.priority != nil {
+		res.Priority = *p.priority
+	}
+	if p.recurrence != nil {
+		res.Recurrence = p.recurrence.String()
+		// 요일 순서 등을 recurrence 패키지의 정규 형식으로 맞춥니다
+		if rule, err := recurrence.Parse(res.Recurrence); err == nil {
+			res.Recurrence = rule.String()
+		}
+	}
+
+	date := p.date
+	if date == nil && p.recurrence != nil {
+		date = p.recurrence.first(p.today())
+	}
+	if date == nil && p.hasClock {
+		today := p.today()
+		date = &today
+		// 이미 지난 시각이면 내일로 잡습니다
+		if time.Date(today.Year(), today.Month(), today.Day(), p.hour, p.min, 0, 0, today.Location()).Before(p.now) {
+			tomorrow := today.AddDate(0, 0, 1)
+			date = &tomorrow
+		}
+	}
+	if date != nil {
+		hour, min := 23, 59
+		switch {
+		case p.hasClock:
+			hour, min = p.hour, p.min
+		case p.tonight:
+			hour, min = 20, 0
+		}
+		due := time.Date(date.Year(), date.Month(), date.Day(), hour, min, 0, 0, date.Location())
+		res.DueDate = &due
+	}
+
+	var title []string
+	for i, word := range p.words {
+		if !p.used[i] {
+			title = append(title, word)
+		}
+	}
+	res.Title = strings.Join(title, " ")
+	return res
+}
+
+// String formats the rule as RRULE
+func (r *rule) String() string {
+	parts := []string{"FREQ=" + r.freq}
+	if r.interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.interval))
+	}
+	if len(r.weekdays) > 0 {
+		codes := make([]string, len(r.weekdays))
+		for i, day := range r.weekdays {
+			codes[i] = weekdayCodes[day]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.monthDay != 0 {
+		parts = append(parts, fmt.Sprintf("BYMONTHDAY=%d", r.monthDay))
+	}
+	return strings.Join(parts, ";")
+}
+
+// first returns the first day on or after today that matches the rule's weekdays or day of month,
+// or nil when the rule does not pin a day
+func (r *rule) first(today time.Time) *time.Time {
+	switch {
+	case len(r.weekdays) > 0:
+		for d := 0; d < 7; d++ {
+			day := today.AddDate(0, 0, d)
+			for _, wd := range r.weekdays {
+				if day.Weekday() == wd {
+					return &day
+				}
+			}
+		}
+	case r.monthDay != 0:
+		day := monthDay(today.Year(), today.Month(), r.monthDay, today.Location())
+		if day.Before(today) {
+			day = monthDay(today.Year(), today.Month()+1, r.monthDay, today.Location())
+		}
+		return &day
+	}
+	return nil
+}
+
+// nextMonthDay returns the next given day of month on or after today
+func (p *parser) nextMonthDay(day int) time.Time {
+	today := p.today()
+	date := monthDay(today.Year(), today.Month(), day, today.Location())
+	if date.Before(today) {
+		date = monthDay(today.Year(), today.Month()+1, day, today.Location())
+	}
+	return date
+}
+
+// calendarDate returns month/day in the given year, or in the next year it has not yet passed when year is 0
+func (p *parser) calendarDate(year int, month time.Month, day int) time.Time {
+	today := p.today()
+	if year != 0 {
+		return time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	}
+	date := time.Date(today.Year(), month, day, 0, 0, 0, 0, today.Location())
+	if date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return date
+}
+
+// monthDay returns the given day of month clamped to the month's length (-1 is the last day)
+func monthDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+	if day == -1 || day > last {
+		day = last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// nextWeekday returns the first given weekday on or after today
+func nextWeekday(today time.Time, day time.Weekday) time.Time {
+	return today.AddDate(0, 0, (int(day)-int(today.Weekday())+7)%7)
+}
+
+// startOfWeek returns the Monday of today's week
+func startOfWeek(today time.Time) time.Time {
+	return today.AddDate(0, 0, -weekdayIndex(today.Weekday()))
+}
+
+// weekdayIndex numbers weekdays from Monday (0) to Sunday (6)
+func weekdayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+func weekday(word string) (time.Weekday, bool) {
+	if day, ok := weekdays[word]; ok {
+		return day, true
+	}
+	day, ok := weekdays[strings.TrimSuffix(word, "s")]
+	return day, ok
+}
+
+// ordinal reads "1st", "2nd", "23rd", "4th" or a plain day number
+func ordinal(word string) (int, bool) {
+	for _, suffix := range []string{"st", "nd", "rd", "th", "일"} {
+		word = strings.TrimSuffix(word, suffix)
+	}
+	n, err := strconv.Atoi(word)
+	if err != nil || n < 1 || n > 31 {
+		return 0, false
+	}
+	return n, true
+}
+
+// slashDate reads "3/15" or "3/15/2025"
+func slashDate(word string) (time.Month, int, int, bool) {
+	parts := strings.Split(word, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, 0, 0, false
+	}
+	month, err := strconv.Atoi(parts[0])
+	if err != nil || month < 1 || month > 12 {
+		return 0, 0, 0, false
+	}
+	day, err := strconv.Atoi(parts[1])
+	if err != nil || day < 1 || day > 31 {
+		return 0, 0, 0, false
+	}
+	year := 0
+	if len(parts) == 3 {
+		if year, err = strconv.Atoi(parts[2]); err != nil || year < 1000 {
+			return 0, 0, 0, false
+		}
+	}
+	return time.Month(month), day, year, true
+}
+
+// clock reads "5pm", "5:30pm", "12am" or "17:30"
+func clock(word string) (int, int, bool) {
+	meridiem := ""
+	if strings.HasSuffix(word, "am") || strings.HasSuffix(word, "pm") {
+		meridiem = word[len(word)-2:]
+		word = word[:len(word)-2]
+	}
+
+	hourPart, minPart, hasMinutes := strings.Cut(word, ":")
+	if meridiem == "" && !hasMinutes {
+		return 0, 0, false
+	}
+	hour, err := strconv.Atoi(hourPart)
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, 0, false
+	}
+	min := 0
+	if hasMinutes {
+		if len(minPart) != 2 {
+			return 0, 0, false
+		}
+		if min, err = strconv.Atoi(minPart); err != nil || min < 0 || min > 59 {
+			return 0, 0, false
+		}
+	}
+
+	if meridiem != "" {
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	return hour, min, true
+}
+
+var priorities = map[string]int{
+	"0": models.PriorityNone, "none": models.PriorityNone,
+	"1": models.PriorityLow, "low": models.PriorityLow,
+	"2": models.PriorityMedium, "medium": models.PriorityMedium, "med": models.PriorityMedium,
+	"3": models.PriorityHigh, "high": models.PriorityHigh,
+	"4": models.PriorityUrgent, "urgent": models.PriorityUrgent,
+}
+
+type frequency struct {
+	freq     string
+	interval int
+}
+
+// units are the period words after "every"; years are expressed as 12 months
+var units = map[string]frequency{
+	"day": {"DAILY", 1}, "days": {"DAILY", 1},
+	"week": {"WEEKLY", 1}, "weeks": {"WEEKLY", 1},
+	"month": {"MONTHLY", 1}, "months": {"MONTHLY", 1},
+	"year": {"MONTHLY", 12}, "years": {"MONTHLY", 12},
+}
+
+var shortcutFrequencies = map[string]frequency{
+	"daily": {"DAILY", 1}, "매일": {"DAILY", 1},
+	"weekly": {"WEEKLY", 1}, "매주": {"WEEKLY", 1},
+	"monthly": {"MONTHLY", 1}, "매월": {"MONTHLY", 1}, "매달": {"MONTHLY", 1},
+	"yearly": {"MONTHLY", 12}, "annually": {"MONTHLY", 12}, "매년": {"MONTHLY", 12},
+}
+
+var relativeDays = map[string]int{
+	"today": 0, "tonight": 0, "오늘": 0,
+	"tomorrow": 1, "tmr": 1, "tmrw": 1, "내일": 1,
+	"모레": 2,
+}
+
+var weekdays = map[string]time.Weekday{
+	"monday": time.Monday, "mon": time.Monday, "월요일": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday, "화요일": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday, "수요일": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday, "목요일": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday, "금요일": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday, "토요일": time.Saturday,
+	"sunday": time.Sunday, "sun": time.Sunday, "일요일": time.Sunday,
+}
+
+var weekdayCodes = map[time.Weekday]string{
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+	time.Sunday:    "SU",
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
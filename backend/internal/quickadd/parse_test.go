@@ -0,0 +1,199 @@
+package quickadd
+
+import (
+	"reflect"
+	"testbox/internal/models"
+	"testbox/internal/recurrence"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// 2025-03-12 is a Wednesday
+	now := time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC)
+	due := func(month time.Month, day, hour, min int) *time.Time {
+		d := time.Date(2025, month, day, hour, min, 0, 0, time.UTC)
+		return &d
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want Result
+	}{
+		{
+			name: "monthly on a day with tag and priority",
+			text: "Pay rent every month on the 1st #finance !high",
+			want: Result{Title: "Pay rent", DueDate: due(time.April, 1, 23, 59), Recurrence: "FREQ=MONTHLY;BYMONTHDAY=1", Tags: []string{"finance"}, Priority: models.PriorityHigh},
+		},
+		{
+			name: "plain title",
+			text: "Write the quarterly summary",
+			want: Result{Title: "Write the quarterly summary", Tags: []string{}},
+		},
+		{
+			name: "tomorrow",
+			text: "Buy milk tomorrow",
+			want: Result{Title: "Buy milk", DueDate: due(time.March, 13, 23, 59), Tags: []string{}},
+		},
+		{
+			name: "today with time",
+			text: "Call mom today at 5pm",
+			want: Result{Title: "Call mom", DueDate: due(time.March, 12, 17, 0), Tags: []string{}},
+		},
+		{
+			name: "tonight",
+			text: "Report due tonight",
+			want: Result{Title: "Report", DueDate: due(time.March, 12, 20, 0), Tags: []string{}},
+		},
+		{
+			name: "tonight with explicit time",
+			text: "Movie tonight at 9pm",
+			want: Result{Title: "Movie", DueDate: due(time.March, 12, 21, 0), Tags: []string{}},
+		},
+		{
+			name: "time only",
+			text: "Lunch at noon",
+			want: Result{Title: "Lunch", DueDate: due(time.March, 12, 12, 0), Tags: []string{}},
+		},
+		{
+			name: "time already passed moves to tomorrow",
+			text: "Stretch 7:30am",
+			want: Result{Title: "Stretch", DueDate: due(time.March, 13, 7, 30), Tags: []string{}},
+		},
+		{
+			name: "weekday recurrence with list and time",
+			text: "Standup every weekday at 9:30am @work",
+			want: Result{Title: "Standup", DueDate: due(time.March, 12, 9, 30), Recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", Tags: []string{}, List: "work"},
+		},
+		{
+			name: "weekday list",
+			text: "Gym every mon, wed and fri",
+			want: Result{Title: "Gym", DueDate: due(time.March, 12, 23, 59), Recurrence: "FREQ=WEEKLY;BYDAY=MO,WE,FR", Tags: []string{}},
+		},
+		{
+			name: "weekdays in canonical order",
+			text: "Review every fri and mon",
+			want: Result{Title: "Review", DueDate: due(time.March, 14, 23, 59), Recurrence: "FREQ=WEEKLY;BYDAY=MO,FR", Tags: []string{}},
+		},
+		{
+			name: "interval without anchor",
+			text: "Water plants every 2 weeks",
+			want: Result{Title: "Water plants", Recurrence: "FREQ=WEEKLY;INTERVAL=2", Tags: []string{}},
+		},
+		{
+			name: "every other day",
+			text: "Run every other day",
+			want: Result{Title: "Run", Recurrence: "FREQ=DAILY;INTERVAL=2", Tags: []string{}},
+		},
+		{
+			name: "yearly as twelve months",
+			text: "Anniversary every year",
+			want: Result{Title: "Anniversary", Recurrence: "FREQ=MONTHLY;INTERVAL=12", Tags: []string{}},
+		},
+		{
+			name: "shortcut with time",
+			text: "Backup daily at 11pm",
+			want: Result{Title: "Backup", DueDate: due(time.March, 12, 23, 0), Recurrence: "FREQ=DAILY", Tags: []string{}},
+		},
+		{
+			name: "next weekday is in the following week",
+			text: "Submit report next friday !urgent",
+			want: Result{Title: "Submit report", DueDate: due(time.March, 21, 23, 59), Tags: []string{}, Priority: models.PriorityUrgent},
+		},
+		{
+			name: "bare weekday",
+			text: "Dentist friday",
+			want: Result{Title: "Dentist", DueDate: due(time.March, 14, 23, 59), Tags: []string{}},
+		},
+		{
+			name: "short weekday alone is not a date",
+			text: "Enjoy the sun",
+			want: Result{Title: "Enjoy the sun", Tags: []string{}},
+		},
+		{
+			name: "relative weeks with two tags",
+			text: "Renew passport in 3 weeks #admin #Travel",
+			want: Result{Title: "Renew passport", DueDate: due(time.April, 2, 23, 59), Tags: []string{"admin", "travel"}},
+		},
+		{
+			name: "month name with preposition",
+			text: "Tax return by apr 15",
+			want: Result{Title: "Tax return", DueDate: due(time.April, 15, 23, 59), Tags: []string{}},
+		},
+		{
+			name: "passed calendar date rolls into next year",
+			text: "Birthday party 15 jan",
+			want: Result{Title: "Birthday party", DueDate: func() *time.Time { d := time.Date(2026, 1, 15, 23, 59, 0, 0, time.UTC); return &d }(), Tags: []string{}},
+		},
+		{
+			name: "iso date with numeric priority",
+			text: "Release 2025-06-01 !2",
+			want: Result{Title: "Release", DueDate: due(time.June, 1, 23, 59), Tags: []string{}, Priority: models.PriorityMedium},
+		},
+		{
+			name: "slash date",
+			text: "Pay invoice 3/20",
+			want: Result{Title: "Pay invoice", DueDate: due(time.March, 20, 23, 59), Tags: []string{}},
+		},
+		{
+			name: "last day of month",
+			text: "Close the books on the last day",
+			want: Result{Title: "Close the books", DueDate: due(time.March, 31, 23, 59), Tags: []string{}},
+		},
+		{
+			name: "unknown priority and dangling preposition stay in the title",
+			text: "Fix bug !important at home",
+			want: Result{Title: "Fix bug !important at home", Tags: []string{}},
+		},
+		{
+			name: "korean date and tag",
+			text: "내일 보고서 제출 #업무 !high",
+			want: Result{Title: "보고서 제출", DueDate: due(time.March, 13, 23, 59), Tags: []string{"업무"}, Priority: models.PriorityHigh},
+		},
+		{
+			name: "korean weekly shortcut with weekday",
+			text: "매주 월요일 주간 회의",
+			want: Result{Title: "주간 회의", DueDate: due(time.March, 17, 23, 59), Recurrence: "FREQ=WEEKLY", Tags: []string{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.text, now)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q)\n got: %+v (due %v)\nwant: %+v (due %v)", tt.text, got, got.DueDate, tt.want, tt.want.DueDate)
+			}
+			if got.Recurrence != "" {
+				if _, err := recurrence.Parse(got.Recurrence); err != nil {
+					t.Errorf("Parse(%q) recurrence %q is not a valid rule: %v", tt.text, got.Recurrence, err)
+				}
+			}
+		})
+	}
+}
+
+func TestClock(t *testing.T) {
+	tests := []struct {
+		word      string
+		hour, min int
+		ok        bool
+	}{
+		{"5pm", 17, 0, true},
+		{"12am", 0, 0, true},
+		{"12pm", 12, 0, true},
+		{"9:05am", 9, 5, true},
+		{"17:30", 17, 30, true},
+		{"13pm", 0, 0, false},
+		{"17", 0, 0, false},
+		{"7:5", 0, 0, false},
+		{"24:00", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		hour, min, ok := clock(tt.word)
+		if hour != tt.hour || min != tt.min || ok != tt.ok {
+			t.Errorf("clock(%q) = %d, %d, %v; want %d, %d, %v", tt.word, hour, min, ok, tt.hour, tt.min, tt.ok)
+		}
+	}
+}
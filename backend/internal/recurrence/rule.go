@@ -0,0 +1,269 @@
+package recurrence
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported frequencies
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+)
+
+// maxIterations bounds the search for the next matching day
+const maxIterations = 1000
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var weekdayNames = map[time.Weekday]string{
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+	time.Sunday:    "SU",
+}
+
+// Rule is a subset of the iCalendar RRULE: FREQ, INTERVAL, BYDAY, BYMONTHDAY, UNTIL and COUNT
+type Rule struct {
+	Freq       string
+	Interval   int
+	ByWeekday  []time.Weekday
+	ByMonthDay int // 1-31, or -1 for the last day of the month
+	Until      *time.Time
+	Count      int
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10".
+// A leading "RRULE:" prefix is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("empty recurrence rule")
+	}
+
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule part: %s", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL: %s", value)
+			}
+			rule.Interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, ok := weekdayCodes[strings.ToUpper(code)]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY: %s", code)
+				}
+				rule.ByWeekday = append(rule.ByWeekday, day)
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(value)
+			if err != nil || n == 0 || n < -1 || n > 31 {
+				return nil, fmt.Errorf("invalid BYMONTHDAY: %s", value)
+			}
+			rule.ByMonthDay = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT: %s", value)
+			}
+			rule.Count = n
+		default:
+			return nil, fmt.Errorf("unsupported rule part: %s", key)
+		}
+	}
+
+	switch rule.Freq {
+	case Daily, Weekly, Monthly:
+	case "":
+		return nil, fmt.Errorf("FREQ is required")
+	default:
+		return nil, fmt.Errorf("unsupported FREQ: %s", rule.Freq)
+	}
+	if rule.Until != nil && rule.Count > 0 {
+		return nil, fmt.Errorf("UNTIL and COUNT cannot be combined")
+	}
+	if rule.ByMonthDay != 0 && rule.Freq != Monthly {
+		return nil, fmt.Errorf("BYMONTHDAY requires FREQ=MONTHLY")
+	}
+
+	sort.Slice(rule.ByWeekday, func(i, j int) bool {
+		return weekdayIndex(rule.ByWeekday[i]) < weekdayIndex(rule.ByWeekday[j])
+	})
+	return rule, nil
+}
+
+// String formats the rule back into its canonical RRULE form
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if len(r.ByWeekday) > 0 {
+		codes := make([]string, len(r.ByWeekday))
+		for i, day := range r.ByWeekday {
+			codes[i] = weekdayNames[day]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.ByMonthDay != 0 {
+		parts = append(parts, fmt.Sprintf("BYMONTHDAY=%d", r.ByMonthDay))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Anchor pins a monthly rule without BYMONTHDAY to the day of month of the series' first
+// occurrence. Next only sees the previous occurrence, so without the anchor a series starting
+// on the 31st would stay on the 28th after February; with it only February is clamped.
+func (r *Rule) Anchor(first time.Time) {
+	if r.Freq == Monthly && r.ByMonthDay == 0 {
+		r.ByMonthDay = first.Day()
+	}
+}
+
+// Next returns the occurrence following prev, where prev is the occurrence-th
+// instance of the series (1-based). It returns false once COUNT or UNTIL is exhausted.
+func (r *Rule) Next(prev time.Time, occurrence int) (time.Time, bool) {
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	switch r.Freq {
+	case Daily:
+		next = r.nextDaily(prev)
+	case Weekly:
+		next = r.nextWeekly(prev)
+	case Monthly:
+		next = r.nextMonthly(prev)
+	default:
+		return time.Time{}, false
+	}
+
+	if next.IsZero() {
+		return time.Time{}, false
+	}
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (r *Rule) nextDaily(prev time.Time) time.Time {
+	next := prev.AddDate(0, 0, r.Interval)
+	if len(r.ByWeekday) == 0 {
+		return next
+	}
+	for i := 0; i < maxIterations; i++ {
+		if r.matchesWeekday(next.Weekday()) {
+			return next
+		}
+		next = next.AddDate(0, 0, r.Interval)
+	}
+	return time.Time{}
+}
+
+func (r *Rule) nextWeekly(prev time.Time) time.Time {
+	if len(r.ByWeekday) == 0 {
+		return prev.AddDate(0, 0, 7*r.Interval)
+	}
+
+	// 같은 주(월요일 시작)에 남은 요일이 있으면 그 날짜를 사용합니다
+	current := weekdayIndex(prev.Weekday())
+	for _, day := range r.ByWeekday {
+		if idx := weekdayIndex(day); idx > current {
+			return prev.AddDate(0, 0, idx-current)
+		}
+	}
+
+	// 없으면 INTERVAL 주 뒤의 첫 번째 요일로 이동합니다
+	weekStart := prev.AddDate(0, 0, -current)
+	return weekStart.AddDate(0, 0, 7*r.Interval+weekdayIndex(r.ByWeekday[0]))
+}
+
+func (r *Rule) nextMonthly(prev time.Time) time.Time {
+	day := prev.Day()
+	if r.ByMonthDay != 0 {
+		day = r.ByMonthDay
+		// 이번 달에 아직 지정일이 남아 있으면 이번 달을 사용합니다
+		if candidate := dayInMonth(prev.Year(), prev.Month(), day, prev); candidate.After(prev) {
+			return candidate
+		}
+	}
+
+	firstOfMonth := time.Date(prev.Year(), prev.Month(), 1, 0, 0, 0, 0, prev.Location())
+	target := firstOfMonth.AddDate(0, r.Interval, 0)
+	return dayInMonth(target.Year(), target.Month(), day, prev)
+}
+
+func (r *Rule) matchesWeekday(day time.Weekday) bool {
+	for _, d := range r.ByWeekday {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// dayInMonth returns the given day of month, clamped to the month's length,
+// keeping the clock time of ref. day == -1 means the last day.
+func dayInMonth(year int, month time.Month, day int, ref time.Time) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, ref.Location()).Day()
+	if day == -1 || day > last {
+		day = last
+	}
+	return time.Date(year, month, day, ref.Hour(), ref.Minute(), ref.Second(), ref.Nanosecond(), ref.Location())
+}
+
+// weekdayIndex numbers weekdays from Monday (0) to Sunday (6)
+func weekdayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL: %s", value)
+}
@@ -0,0 +1,217 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 9, 30, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []time.Time // occurrences after start, in order; the series ends after the last one
+		more  bool        // whether the series continues past want
+	}{
+		{
+			name:  "daily",
+			rule:  "FREQ=DAILY",
+			start: day(2025, time.December, 30),
+			want:  []time.Time{day(2025, time.December, 31), day(2026, time.January, 1)},
+			more:  true,
+		},
+		{
+			name:  "daily every third day",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: day(2025, time.February, 26),
+			want:  []time.Time{day(2025, time.March, 1), day(2025, time.March, 4)},
+			more:  true,
+		},
+		{
+			name:  "daily on weekdays",
+			rule:  "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			start: day(2025, time.March, 13), // Thursday
+			want:  []time.Time{day(2025, time.March, 14), day(2025, time.March, 17), day(2025, time.March, 18)},
+			more:  true,
+		},
+		{
+			name:  "weekly",
+			rule:  "FREQ=WEEKLY",
+			start: day(2025, time.March, 12),
+			want:  []time.Time{day(2025, time.March, 19), day(2025, time.March, 26)},
+			more:  true,
+		},
+		{
+			name:  "weekly on several days",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			start: day(2025, time.March, 12), // Wednesday
+			want:  []time.Time{day(2025, time.March, 14), day(2025, time.March, 17), day(2025, time.March, 19)},
+			more:  true,
+		},
+		{
+			name:  "every other week on Monday and Thursday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start: day(2025, time.March, 10), // Monday
+			want:  []time.Time{day(2025, time.March, 13), day(2025, time.March, 24), day(2025, time.March, 27), day(2025, time.April, 7)},
+			more:  true,
+		},
+		{
+			name:  "monthly from the 31st returns to the 31st",
+			rule:  "FREQ=MONTHLY",
+			start: day(2025, time.January, 31),
+			want:  []time.Time{day(2025, time.February, 28), day(2025, time.March, 31), day(2025, time.April, 30), day(2025, time.May, 31)},
+			more:  true,
+		},
+		{
+			name:  "monthly through February of a leap year",
+			rule:  "FREQ=MONTHLY",
+			start: day(2024, time.January, 30),
+			want:  []time.Time{day(2024, time.February, 29), day(2024, time.March, 30)},
+			more:  true,
+		},
+		{
+			name:  "monthly on the 29th across a leap year boundary",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=29",
+			start: day(2024, time.January, 29),
+			want:  []time.Time{day(2024, time.February, 29), day(2024, time.March, 29)},
+			more:  true,
+		},
+		{
+			name:  "monthly on the 29th in a common year",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=29",
+			start: day(2025, time.January, 29),
+			want:  []time.Time{day(2025, time.February, 28), day(2025, time.March, 29)},
+			more:  true,
+		},
+		{
+			name:  "last day of the month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: day(2024, time.January, 31),
+			want:  []time.Time{day(2024, time.February, 29), day(2024, time.March, 31), day(2024, time.April, 30)},
+			more:  true,
+		},
+		{
+			name:  "BYMONTHDAY later in the starting month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=15",
+			start: day(2025, time.March, 10),
+			want:  []time.Time{day(2025, time.March, 15), day(2025, time.April, 15)},
+			more:  true,
+		},
+		{
+			name:  "quarterly from the 31st",
+			rule:  "FREQ=MONTHLY;INTERVAL=3",
+			start: day(2025, time.January, 31),
+			want:  []time.Time{day(2025, time.April, 30), day(2025, time.July, 31), day(2025, time.October, 31)},
+			more:  true,
+		},
+		{
+			name:  "yearly as twelve months from a leap day",
+			rule:  "FREQ=MONTHLY;INTERVAL=12",
+			start: day(2024, time.February, 29),
+			want:  []time.Time{day(2025, time.February, 28), day(2026, time.February, 28), day(2027, time.February, 28), day(2028, time.February, 29)},
+			more:  true,
+		},
+		{
+			name:  "COUNT includes the first occurrence",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: day(2025, time.March, 1),
+			want:  []time.Time{day(2025, time.March, 2), day(2025, time.March, 3)},
+		},
+		{
+			name:  "UNTIL includes an occurrence at that moment",
+			rule:  "FREQ=WEEKLY;UNTIL=20250326T093000Z",
+			start: day(2025, time.March, 12),
+			want:  []time.Time{day(2025, time.March, 19), day(2025, time.March, 26)},
+		},
+		{
+			name:  "UNTIL as a date",
+			rule:  "FREQ=MONTHLY;UNTIL=20250501",
+			start: day(2025, time.January, 31),
+			want:  []time.Time{day(2025, time.February, 28), day(2025, time.March, 31), day(2025, time.April, 30)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+			rule.Anchor(tt.start)
+
+			prev := tt.start
+			for i, want := range tt.want {
+				got, ok := rule.Next(prev, i+1)
+				if !ok || !got.Equal(want) {
+					t.Fatalf("occurrence %d: Next(%s) = %s, %v; want %s", i+2, prev.Format(time.DateOnly), got.Format(time.DateOnly), ok, want.Format(time.DateOnly))
+				}
+				prev = got
+			}
+
+			if _, ok := rule.Next(prev, len(tt.want)+1); ok != tt.more {
+				t.Errorf("after %s: series continues = %v, want %v", prev.Format(time.DateOnly), ok, tt.more)
+			}
+		})
+	}
+}
+
+func TestAnchorKeepsExplicitMonthDay(t *testing.T) {
+	rule, err := Parse("FREQ=MONTHLY;BYMONTHDAY=-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule.Anchor(time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC))
+	if got := rule.String(); got != "FREQ=MONTHLY;BYMONTHDAY=-1" {
+		t.Errorf("String() = %s, want FREQ=MONTHLY;BYMONTHDAY=-1", got)
+	}
+
+	weekly, err := Parse("FREQ=WEEKLY;BYDAY=MO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	weekly.Anchor(time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC))
+	if got := weekly.String(); got != "FREQ=WEEKLY;BYDAY=MO" {
+		t.Errorf("String() = %s, want FREQ=WEEKLY;BYDAY=MO", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{rule: "RRULE:freq=weekly;byday=fr,mo", want: "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{rule: "FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=31", want: "FREQ=MONTHLY;BYMONTHDAY=31"},
+		{rule: "FREQ=DAILY;UNTIL=20250501", want: "FREQ=DAILY;UNTIL=20250501T000000Z"},
+		{rule: "", wantErr: true},
+		{rule: "INTERVAL=2", wantErr: true},
+		{rule: "FREQ=YEARLY", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=3;UNTIL=20250501", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		rule, err := Parse(tt.rule)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %s, want error", tt.rule, rule)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.rule, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %s, want %s", tt.rule, got, tt.want)
+		}
+	}
+}
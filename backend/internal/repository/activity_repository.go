@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"testbox/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ActivityRepository interface {
+	Create(activity *models.Activity) error
+	FindByTodo(todoID string) ([]models.Activity, error)
+}
+
+type activityRepository struct {
+	db *gorm.DB
+}
+
+func NewActivityRepository(db *gorm.DB) ActivityRepository {
+	return &activityRepository{db: db}
+}
+
+func (r *activityRepository) Create(activity *models.Activity) error {
+	return r.db.Create(activity).Error
+}
+
+// FindByTodo returns the todo's activities, oldest first
+func (r *activityRepository) FindByTodo(todoID string) ([]models.Activity, error) {
+	var activities []models.Activity
+	if err := r.db.Where("todo_id = ?", todoID).Order("created_at ASC").Find(&activities).Error; err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
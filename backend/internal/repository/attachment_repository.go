@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testbox/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository interface {
+	Create(attachment *models.Attachment) error
+	FindByID(id string) (*models.Attachment, error)
+	FindByOwner(ownerType, ownerID string) ([]models.Attachment, error)
+	FindOrphans(limit int) ([]models.Attachment, error)
+	Delete(id string) error
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Create(attachment *models.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *attachmentRepository) FindByID(id string) (*models.Attachment, error) {
+	var attachment models.Attachment
+	if err := r.db.First(&attachment, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// FindByOwner returns the attachments of a todo or blog post, oldest first
+func (r *attachmentRepository) FindByOwner(ownerType, ownerID string) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("created_at ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+// FindOrphans returns attachments whose todo or blog post no longer exists
+func (r *attachmentRepository) FindOrphans(limit int) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.Where(`
+		(owner_type = ? AND NOT EXISTS (SELECT 1 FROM todos WHERE todos.id = attachments.owner_id))
+		OR (owner_type = ? AND NOT EXISTS (SELECT 1 FROM blog_posts WHERE blog_posts.id = attachments.owner_id))`,
+		models.AttachmentOwnerTodo, models.AttachmentOwnerBlog).
+		Limit(limit).
+		Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *attachmentRepository) Delete(id string) error {
+	return r.db.Delete(&models.Attachment{}, "id = ?", id).Error
+}
@@ -2,16 +2,27 @@ package repository
 
 import (
 	"testbox/internal/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BlogRepository interface {
 	Create(blog *models.BlogPost) error
 	FindByID(id string) (*models.BlogPost, error)
-	FindAll() ([]models.BlogPost, error)
+	FindBySlug(slug string) (*models.BlogPost, error)
+	FindByFormerSlug(slug string) (*models.BlogPost, error)
+	SlugTaken(slug, exceptID string) (bool, error)
+	RecordSlugChange(id, oldSlug, newSlug string) error
+	FindAll(tags []string, status string) ([]models.BlogPost, error)
 	Update(blog *models.BlogPost) error
+	ReplaceTags(blog *models.BlogPost, tags []models.Tag) error
 	Delete(id string) error
+	FindTrash() ([]models.BlogPost, error)
+	Restore(id string) (bool, error)
+	FindDeletedBefore(cutoff time.Time, limit int) ([]string, error)
+	Purge(ids []string) error
 }
 
 type blogRepository struct {
@@ -28,24 +39,146 @@ func (r *blogRepository) Create(blog *models.BlogPost) error {
 
 func (r *blogRepository) FindByID(id string) (*models.BlogPost, error) {
 	var blog models.BlogPost
-	if err := r.db.First(&blog, "id = ?", id).Error; err != nil {
+	if err := r.db.Preload("Tags").First(&blog, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &blog, nil
 }
 
-func (r *blogRepository) FindAll() ([]models.BlogPost, error) {
+func (r *blogRepository) FindBySlug(slug string) (*models.BlogPost, error) {
+	var blog models.BlogPost
+	if err := r.db.Preload("Tags").First(&blog, "slug = ?", slug).Error; err != nil {
+		return nil, err
+	}
+	return &blog, nil
+}
+
+// FindByFormerSlug returns the post that used to have the slug before it was renamed
+func (r *blogRepository) FindByFormerSlug(slug string) (*models.BlogPost, error) {
+	var blog models.BlogPost
+	err := r.db.Preload("Tags").
+		Where("id = (SELECT blog_post_id FROM blog_slugs WHERE slug = ?)", slug).
+		First(&blog).Error
+	if err != nil {
+		return nil, err
+	}
+	return &blog, nil
+}
+
+// SlugTaken reports whether another post, including posts in the trash, has or had the slug
+func (r *blogRepository) SlugTaken(slug, exceptID string) (bool, error) {
+	var taken bool
+	err := r.db.Raw(`SELECT
+		EXISTS (SELECT 1 FROM blog_posts WHERE slug = ? AND id::text <> ?) OR
+		EXISTS (SELECT 1 FROM blog_slugs WHERE slug = ? AND blog_post_id::text <> ?)`,
+		slug, exceptID, slug, exceptID).Scan(&taken).Error
+	return taken, err
+}
+
+// RecordSlugChange keeps oldSlug as a former slug of the post. A former slug the post
+// takes back (newSlug) stops being a redirect.
+func (r *blogRepository) RecordSlugChange(id, oldSlug, newSlug string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if oldSlug != "" {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "slug"}},
+				DoUpdates: clause.AssignmentColumns([]string{"blog_post_id", "created_at"}),
+			}).Create(&models.BlogSlug{Slug: oldSlug, BlogPostID: id}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("slug = ? AND blog_post_id = ?", newSlug, id).Delete(&models.BlogSlug{}).Error
+	})
+}
+
+// FindAll returns blog posts newest first by publication (or creation) time. When tags are
+// given, only posts carrying all of them; when status is given, only posts in that status.
+func (r *blogRepository) FindAll(tags []string, status string) ([]models.BlogPost, error) {
+	db := r.db.Preload("Tags")
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	if len(tags) > 0 {
+		db = db.Where(`id IN (
+			SELECT blog_post_tags.blog_post_id FROM blog_post_tags
+			JOIN tags ON tags.id = blog_post_tags.tag_id
+			WHERE tags.name IN ?
+			GROUP BY blog_post_tags.blog_post_id
+			HAVING COUNT(DISTINCT tags.id) = ?)`, tags, len(tags))
+	}
+
 	var blogs []models.BlogPost
-	if err := r.db.Order("created_at DESC").Find(&blogs).Error; err != nil {
+	if err := db.Order("COALESCE(published_at, created_at) DESC, created_at DESC").Find(&blogs).Error; err != nil {
 		return nil, err
 	}
 	return blogs, nil
 }
 
+// Update saves the post's own columns; tags are changed through ReplaceTags
 func (r *blogRepository) Update(blog *models.BlogPost) error {
-	return r.db.Save(blog).Error
+	return r.db.Omit(clause.Associations).Save(blog).Error
+}
+
+// ReplaceTags sets the post's tags to exactly the given tags
+func (r *blogRepository) ReplaceTags(blog *models.BlogPost, tags []models.Tag) error {
+	return r.db.Model(blog).Association("Tags").Replace(tags)
 }
 
+// Delete moves the post to the trash; its tag links are kept until the purge
 func (r *blogRepository) Delete(id string) error {
-	return r.db.Delete(&models.BlogPost{}, "id = ?", id).Error
+	result := r.db.Delete(&models.BlogPost{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindTrash returns the posts in the trash, most recently deleted first
+func (r *blogRepository) FindTrash() ([]models.BlogPost, error) {
+	var blogs []models.BlogPost
+	if err := r.db.Unscoped().Preload("Tags").Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&blogs).Error; err != nil {
+		return nil, err
+	}
+	return blogs, nil
+}
+
+// Restore takes the post out of the trash and reports whether it was there
+func (r *blogRepository) Restore(id string) (bool, error) {
+	result := r.db.Unscoped().Model(&models.BlogPost{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindDeletedBefore returns up to limit IDs of posts that were moved to the trash before cutoff
+func (r *blogRepository) FindDeletedBefore(cutoff time.Time, limit int) ([]string, error) {
+	var ids []string
+	err := r.db.Unscoped().Model(&models.BlogPost{}).
+		Where("deleted_at < ?", cutoff).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Purge permanently removes the posts together with their tag links, revisions and former slugs
+func (r *blogRepository) Purge(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM blog_post_tags WHERE blog_post_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM revisions WHERE owner_type = ? AND owner_id IN ?", models.RevisionOwnerBlog, ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM blog_slugs WHERE blog_post_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.BlogPost{}, "id IN ?", ids).Error
+	})
 }
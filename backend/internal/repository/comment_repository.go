@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"testbox/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type CommentRepository interface {
+	Create(comment *models.Comment) error
+	FindByID(id string) (*models.Comment, error)
+	FindByTodo(todoID string) ([]models.Comment, error)
+	Update(comment *models.Comment) error
+	Delete(id string) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) Create(comment *models.Comment) error {
+	return r.db.Create(comment).Error
+}
+
+func (r *commentRepository) FindByID(id string) (*models.Comment, error) {
+	var comment models.Comment
+	if err := r.db.First(&comment, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// FindByTodo returns the todo's comments, oldest first
+func (r *commentRepository) FindByTodo(todoID string) ([]models.Comment, error) {
+	var comments []models.Comment
+	if err := r.db.Where("todo_id = ?", todoID).Order("created_at ASC").Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *commentRepository) Update(comment *models.Comment) error {
+	return r.db.Save(comment).Error
+}
+
+func (r *commentRepository) Delete(id string) error {
+	return r.db.Delete(&models.Comment{}, "id = ?", id).Error
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"errors"
+	"testbox/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrDependencyCycle is returned when a new dependency would make a todo (indirectly) block itself
+var ErrDependencyCycle = errors.New("dependency would create a cycle")
+
+type DependencyRepository interface {
+	Add(todoID, blockerID string) error
+	Remove(todoID, blockerID string) (bool, error)
+	FindBlockers(todoID string) ([]models.Todo, error)
+	FindDependents(todoID string) ([]models.Todo, error)
+	CountOpenBlockers(todoID string) (int64, error)
+}
+
+type dependencyRepository struct {
+	db *gorm.DB
+}
+
+func NewDependencyRepository(db *gorm.DB) DependencyRepository {
+	return &dependencyRepository{db: db}
+}
+
+// Add records that todoID is blocked by blockerID. It fails with ErrDependencyCycle
+// if blockerID is already (transitively) blocked by todoID. Adding an existing edge is a no-op.
+func (r *dependencyRepository) Add(todoID, blockerID string) error {
+	if todoID == blockerID {
+		return ErrDependencyCycle
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 동시에 추가되는 두 간선이 함께 순환을 만들지 않도록 간선 추가를 직렬화합니다
+		if err := tx.Exec("LOCK TABLE todo_dependencies IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		var cycle bool
+		err := tx.Raw(`
+			WITH RECURSIVE chain AS (
+				SELECT blocker_id FROM todo_dependencies WHERE todo_id = ?
+				UNION
+				SELECT d.blocker_id FROM todo_dependencies d JOIN chain c ON d.todo_id = c.blocker_id
+			)
+			SELECT EXISTS (SELECT 1 FROM chain WHERE blocker_id = ?)`, blockerID, todoID).Scan(&cycle).Error
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrDependencyCycle
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.TodoDependency{TodoID: todoID, BlockerID: blockerID}).Error
+	})
+}
+
+// Remove deletes the dependency and reports whether it existed
+func (r *dependencyRepository) Remove(todoID, blockerID string) (bool, error) {
+	result := r.db.Delete(&models.TodoDependency{}, "todo_id = ? AND blocker_id = ?", todoID, blockerID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindBlockers returns the todos that todoID is waiting on
+func (r *dependencyRepository) FindBlockers(todoID string) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := r.db.Preload("Tags").
+		Where("id IN (SELECT blocker_id FROM todo_dependencies WHERE todo_id = ?)", todoID).
+		Order("position ASC").
+		Find(&todos).Error
+	return todos, err
+}
+
+// FindDependents returns the todos waiting on todoID
+func (r *dependencyRepository) FindDependents(todoID string) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := r.db.Preload("Tags").
+		Where("id IN (SELECT todo_id FROM todo_dependencies WHERE blocker_id = ?)", todoID).
+		Order("position ASC").
+		Find(&todos).Error
+	return todos, err
+}
+
+// CountOpenBlockers returns how many of todoID's blockers are not completed yet
+func (r *dependencyRepository) CountOpenBlockers(todoID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Todo{}).
+		Where("id IN (SELECT blocker_id FROM todo_dependencies WHERE todo_id = ?)", todoID).
+		Where("completed = ?", false).
+		Count(&count).Error
+	return count, err
+}
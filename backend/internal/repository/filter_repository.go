@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testbox/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type FilterRepository interface {
+	Create(filter *models.SavedFilter) error
+	FindByID(id string) (*models.SavedFilter, error)
+	FindByUser(userID string) ([]models.SavedFilter, error)
+	Update(filter *models.SavedFilter) error
+	Delete(id string) error
+}
+
+type filterRepository struct {
+	db *gorm.DB
+}
+
+func NewFilterRepository(db *gorm.DB) FilterRepository {
+	return &filterRepository{db: db}
+}
+
+func (r *filterRepository) Create(filter *models.SavedFilter) error {
+	return r.db.Create(filter).Error
+}
+
+func (r *filterRepository) FindByID(id string) (*models.SavedFilter, error) {
+	var filter models.SavedFilter
+	if err := r.db.First(&filter, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
+// FindByUser returns the user's saved filters, oldest first
+func (r *filterRepository) FindByUser(userID string) ([]models.SavedFilter, error) {
+	var filters []models.SavedFilter
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC, id ASC").Find(&filters).Error; err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+func (r *filterRepository) Update(filter *models.SavedFilter) error {
+	return r.db.Save(filter).Error
+}
+
+// Delete removes the filter; it returns gorm.ErrRecordNotFound if it does not exist
+func (r *filterRepository) Delete(id string) error {
+	result := r.db.Delete(&models.SavedFilter{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
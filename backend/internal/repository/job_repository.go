@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"testbox/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository interface {
+	Upsert(job *models.ScheduledJob) error
+	ClaimDue(now time.Time, limit int, lease time.Duration) ([]models.ScheduledJob, error)
+	Finish(job *models.ScheduledJob) error
+	DeleteByKey(key string) error
+}
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+// Upsert creates the job or replaces the schedule of an existing job with the same key
+func (r *jobRepository) Upsert(job *models.ScheduledJob) error {
+	if job.Status == "" {
+		job.Status = models.JobPending
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"kind":         job.Kind,
+			"payload":      job.Payload,
+			"cron_spec":    job.CronSpec,
+			"run_at":       job.RunAt,
+			"status":       models.JobPending,
+			"attempts":     0,
+			"last_error":   "",
+			"locked_until": nil,
+			"updated_at":   time.Now(),
+		}),
+	}).Create(job).Error
+}
+
+// ClaimDue locks up to limit due jobs for this process. Jobs whose lease expired
+// (e.g. the previous owner crashed) are claimed again. SKIP LOCKED lets several
+// backend instances poll the same table without picking the same job.
+func (r *jobRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]models.ScheduledJob, error) {
+	var jobs []models.ScheduledJob
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)",
+				models.JobPending, now, models.JobRunning, now).
+			Order("run_at ASC").
+			Limit(limit).
+			Find(&jobs).Error; err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(jobs))
+		lockedUntil := now.Add(lease)
+		for i := range jobs {
+			ids[i] = jobs[i].ID
+			jobs[i].Status = models.JobRunning
+			jobs[i].LockedUntil = &lockedUntil
+		}
+
+		return tx.Model(&models.ScheduledJob{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       models.JobRunning,
+				"locked_until": lockedUntil,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// Finish stores the outcome of a run. The update only applies while the job is
+// still marked running, so a reschedule (Upsert) made during the run wins.
+func (r *jobRepository) Finish(job *models.ScheduledJob) error {
+	return r.db.Model(&models.ScheduledJob{}).
+		Where("id = ? AND status = ?", job.ID, models.JobRunning).
+		Updates(map[string]interface{}{
+			"status":       job.Status,
+			"run_at":       job.RunAt,
+			"attempts":     job.Attempts,
+			"last_error":   job.LastError,
+			"locked_until": nil,
+		}).Error
+}
+
+func (r *jobRepository) DeleteByKey(key string) error {
+	return r.db.Delete(&models.ScheduledJob{}, "key = ?", key).Error
+}
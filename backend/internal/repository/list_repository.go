@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"testbox/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ListRepository interface {
+	Create(list *models.List) error
+	FindByID(id string) (*models.List, error)
+	FindByName(name string) (*models.List, error)
+	FindAllWithCounts() ([]models.ListSummary, error)
+	CountTodos(id string) (*models.ListCounts, error)
+	Update(list *models.List) error
+	Delete(id string) ([]string, error)
+}
+
+type listRepository struct {
+	db *gorm.DB
+}
+
+func NewListRepository(db *gorm.DB) ListRepository {
+	return &listRepository{db: db}
+}
+
+func (r *listRepository) Create(list *models.List) error {
+	return r.db.Create(list).Error
+}
+
+func (r *listRepository) FindByID(id string) (*models.List, error) {
+	var list models.List
+	if err := r.db.First(&list, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// FindByName returns the oldest list with the given name, ignoring case
+func (r *listRepository) FindByName(name string) (*models.List, error) {
+	var list models.List
+	if err := r.db.Where("LOWER(name) = LOWER(?)", name).Order("created_at ASC").First(&list).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// FindAllWithCounts returns every list with its open/done todo counts in one query
+func (r *listRepository) FindAllWithCounts() ([]models.ListSummary, error) {
+	var lists []models.ListSummary
+	err := r.db.Raw(`
+		SELECT lists.*,
+			COUNT(todos.id) FILTER (WHERE todos.completed = false) AS open_count,
+			COUNT(todos.id) FILTER (WHERE todos.completed = true) AS done_count
+		FROM lists
+		LEFT JOIN todos ON todos.list_id = lists.id AND todos.deleted_at IS NULL
+		GROUP BY lists.id
+		ORDER BY lists.created_at ASC`).Scan(&lists).Error
+	if err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+func (r *listRepository) CountTodos(id string) (*models.ListCounts, error) {
+	var counts models.ListCounts
+	err := r.db.Model(&models.Todo{}).
+		Select("COUNT(*) FILTER (WHERE completed = false) AS open_count, COUNT(*) FILTER (WHERE completed = true) AS done_count").
+		Where("list_id = ?", id).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+func (r *listRepository) Update(list *models.List) error {
+	return r.db.Save(list).Error
+}
+
+// Delete removes the list and moves its todos, including those in the trash, back to
+// the inbox (no list). It returns the IDs of the live todos that were detached.
+func (r *listRepository) Delete(id string) ([]string, error) {
+	var todoIDs []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Todo{}).Where("list_id = ?", id).Pluck("id", &todoIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.Todo{}).Where("list_id = ?", id).Update("list_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.List{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todoIDs, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"testbox/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type RevisionRepository interface {
+	Create(revision *models.Revision) error
+	FindByOwner(ownerType, ownerID string) ([]models.Revision, error)
+	FindByNumber(ownerType, ownerID string, number int) (*models.Revision, error)
+	FindLatest(ownerType, ownerID string) (*models.Revision, error)
+}
+
+type revisionRepository struct {
+	db *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+// Create stores the revision with the next number for its owner.
+// Revisions of the same owner are numbered one at a time under an advisory lock.
+func (r *revisionRepository) Create(revision *models.Revision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", revision.OwnerType+":"+revision.OwnerID).Error; err != nil {
+			return err
+		}
+
+		var last int
+		err := tx.Model(&models.Revision{}).
+			Where("owner_type = ? AND owner_id = ?", revision.OwnerType, revision.OwnerID).
+			Select("COALESCE(MAX(number), 0)").
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+
+		revision.Number = last + 1
+		return tx.Create(revision).Error
+	})
+}
+
+// FindByOwner returns the owner's revisions, newest first
+func (r *revisionRepository) FindByOwner(ownerType, ownerID string) ([]models.Revision, error) {
+	var revisions []models.Revision
+	err := r.db.Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("number DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *revisionRepository) FindByNumber(ownerType, ownerID string, number int) (*models.Revision, error) {
+	var revision models.Revision
+	err := r.db.Where("owner_type = ? AND owner_id = ? AND number = ?", ownerType, ownerID, number).
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// FindLatest returns the owner's newest revision, or nil if it has none
+func (r *revisionRepository) FindLatest(ownerType, ownerID string) (*models.Revision, error) {
+	var revisions []models.Revision
+	err := r.db.Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("number DESC").
+		Limit(1).
+		Find(&revisions).Error
+	if err != nil || len(revisions) == 0 {
+		return nil, err
+	}
+	return &revisions[0], nil
+}
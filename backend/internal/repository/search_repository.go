@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"testbox/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Page size limits for SearchQuery.Limit
+const (
+	DefaultSearchPageSize = 20
+	MaxSearchPageSize     = 100
+)
+
+// SearchQuery selects full-text search results
+type SearchQuery struct {
+	Text          string   // web search syntax: words, "quoted phrases", OR and -excluded words
+	Types         []string // models.SearchTypeTodo and/or models.SearchTypeBlog; empty searches both
+	Limit         int
+	Offset        int
+	PublishedOnly bool // leaves out blog posts that are not published
+}
+
+// SearchPage is a page of search results, best match first
+type SearchPage struct {
+	Items []models.SearchResult `json:"items"`
+	Total int64                 `json:"total"`
+}
+
+// searchSources are the searchable tables; their search_vector columns are kept current
+// by the triggers created in database.migrateSearch
+var searchSources = map[string]string{
+	models.SearchTypeTodo: "todos",
+	models.SearchTypeBlog: "blog_posts",
+}
+
+const (
+	searchTitleOptions   = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	searchSnippetOptions = `StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=" … "`
+)
+
+type SearchRepository interface {
+	Search(query SearchQuery) (*SearchPage, error)
+}
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+// Search ranks the todos and blog posts in the trash-free tables against the query.
+// Only the returned page is highlighted because ts_headline has to re-parse each document.
+func (r *searchRepository) Search(query SearchQuery) (*SearchPage, error) {
+	types := query.Types
+	if len(types) == 0 {
+		types = []string{models.SearchTypeTodo, models.SearchTypeBlog}
+	}
+
+	var branches []string
+	for _, t := range types {
+		table, ok := searchSources[t]
+		if !ok {
+			return nil, fmt.Errorf("unknown search type: %s", t)
+		}
+		filter := ""
+		if t == models.SearchTypeBlog && query.PublishedOnly {
+			filter = fmt.Sprintf(" AND status = '%s'", models.BlogPublished)
+		}
+		branches = append(branches, fmt.Sprintf(`
+			SELECT '%s' AS type, id, title, content, updated_at, ts_rank_cd(search_vector, query.q, 1) AS rank
+			FROM %s, query
+			WHERE deleted_at IS NULL AND search_vector @@ query.q%s`, t, table, filter))
+	}
+	matches := fmt.Sprintf(`
+		WITH query AS (SELECT websearch_to_tsquery('simple', ?) AS q),
+		matches AS (%s)`, strings.Join(branches, " UNION ALL "))
+
+	var total int64
+	if err := r.db.Raw(matches+" SELECT COUNT(*) FROM matches", query.Text).Scan(&total).Error; err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultSearchPageSize
+	}
+	if limit > MaxSearchPageSize {
+		limit = MaxSearchPageSize
+	}
+
+	page := &SearchPage{Items: []models.SearchResult{}, Total: total}
+	if total == 0 || query.Offset >= int(total) {
+		return page, nil
+	}
+
+	err := r.db.Raw(matches+fmt.Sprintf(`
+		SELECT type, id,
+			ts_headline('simple', %s, query.q, ?) AS title,
+			ts_headline('simple', %s, query.q, ?) AS snippet,
+			rank, updated_at
+		FROM (SELECT * FROM matches ORDER BY rank DESC, updated_at DESC, id LIMIT ? OFFSET ?) ranked, query
+		ORDER BY rank DESC, updated_at DESC, id`, escapeHTMLColumn("title"), escapeHTMLColumn("content")),
+		query.Text, searchTitleOptions, searchSnippetOptions, limit, query.Offset,
+	).Scan(&page.Items).Error
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// escapeHTMLColumn escapes a text column in SQL so highlighted results can be shown as HTML
+func escapeHTMLColumn(column string) string {
+	return fmt.Sprintf("replace(replace(replace(%s, '&', '&amp;'), '<', '&lt;'), '>', '&gt;')", column)
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"testbox/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository interface {
+	FindOrCreate(names []string) ([]models.Tag, error)
+	FindByID(id string) (*models.Tag, error)
+	FindByName(name string) (*models.Tag, error)
+	FindAllWithUsage() ([]models.TagUsage, error)
+	Update(tag *models.Tag) error
+	Merge(sourceID, targetID string) error
+	FindTodoIDs(tagID string) ([]string, error)
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// FindOrCreate returns the tags with the given (already normalized) names, creating missing ones
+func (r *tagRepository) FindOrCreate(names []string) ([]models.Tag, error) {
+	if len(names) == 0 {
+		return []models.Tag{}, nil
+	}
+
+	newTags := make([]models.Tag, len(names))
+	for i, name := range names {
+		newTags[i] = models.Tag{Name: name}
+	}
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&newTags).Error; err != nil {
+		return nil, err
+	}
+
+	var tags []models.Tag
+	if err := r.db.Where("name IN ?", names).Order("name ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) FindByID(id string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.First(&tag, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) FindByName(name string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.First(&tag, "name = ?", name).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindAllWithUsage returns every tag with the number of todos and blog posts using it.
+// Items in the trash are not counted.
+func (r *tagRepository) FindAllWithUsage() ([]models.TagUsage, error) {
+	var tags []models.TagUsage
+	err := r.db.Raw(`
+		SELECT tags.*,
+			(SELECT COUNT(*) FROM todo_tags
+				JOIN todos ON todos.id = todo_tags.todo_id
+				WHERE todo_tags.tag_id = tags.id AND todos.deleted_at IS NULL) AS todo_count,
+			(SELECT COUNT(*) FROM blog_post_tags
+				JOIN blog_posts ON blog_posts.id = blog_post_tags.blog_post_id
+				WHERE blog_post_tags.tag_id = tags.id AND blog_posts.deleted_at IS NULL) AS blog_count
+		FROM tags
+		ORDER BY tags.name ASC`).Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) Update(tag *models.Tag) error {
+	return r.db.Save(tag).Error
+}
+
+// Merge moves every todo and blog post from the source tag to the target tag
+// and deletes the source tag
+func (r *tagRepository) Merge(sourceID, targetID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO todo_tags (todo_id, tag_id)
+			SELECT todo_id, ? FROM todo_tags WHERE tag_id = ?
+			ON CONFLICT DO NOTHING`, targetID, sourceID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`
+			INSERT INTO blog_post_tags (blog_post_id, tag_id)
+			SELECT blog_post_id, ? FROM blog_post_tags WHERE tag_id = ?
+			ON CONFLICT DO NOTHING`, targetID, sourceID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM todo_tags WHERE tag_id = ?", sourceID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM blog_post_tags WHERE tag_id = ?", sourceID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, "id = ?", sourceID).Error
+	})
+}
+
+// FindTodoIDs returns the IDs of todos tagged with the given tag
+func (r *tagRepository) FindTodoIDs(tagID string) ([]string, error) {
+	var ids []string
+	if err := r.db.Table("todo_tags").Where("tag_id = ?", tagID).Pluck("todo_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
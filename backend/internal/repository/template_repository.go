@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testbox/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type TemplateRepository interface {
+	Create(template *models.Template) error
+	FindByID(id string) (*models.Template, error)
+	FindAll() ([]models.Template, error)
+	Update(template *models.Template) error
+	Delete(id string) error
+}
+
+type templateRepository struct {
+	db *gorm.DB
+}
+
+func NewTemplateRepository(db *gorm.DB) TemplateRepository {
+	return &templateRepository{db: db}
+}
+
+func (r *templateRepository) Create(template *models.Template) error {
+	return r.db.Create(template).Error
+}
+
+func (r *templateRepository) FindByID(id string) (*models.Template, error) {
+	var template models.Template
+	if err := r.db.First(&template, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindAll returns every template ordered by name
+func (r *templateRepository) FindAll() ([]models.Template, error) {
+	var templates []models.Template
+	if err := r.db.Order("name ASC").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *templateRepository) Update(template *models.Template) error {
+	return r.db.Save(template).Error
+}
+
+// Delete removes the template; it returns gorm.ErrRecordNotFound if it does not exist
+func (r *templateRepository) Delete(id string) error {
+	result := r.db.Delete(&models.Template{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
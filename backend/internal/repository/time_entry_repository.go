@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"fmt"
+	"testbox/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Groupings supported by TimeReportQuery.GroupBy
+const (
+	TimeReportByTodo = "todo"
+	TimeReportByList = "list"
+	TimeReportByDay  = "day"
+)
+
+// TimeReportQuery selects the entries summed by a time report
+type TimeReportQuery struct {
+	GroupBy  string     // "todo", "list" or "day"
+	UserID   string     // only entries of this user
+	TodoID   string     // only entries of this todo
+	ListID   *string    // only entries of todos in this list; "" selects todos without a list
+	From     *time.Time // started_at >= From
+	To       *time.Time // started_at < To
+	Location string     // IANA time zone used to bucket days (default UTC)
+}
+
+type TimeEntryRepository interface {
+	Create(entry *models.TimeEntry) error
+	FindByID(id string) (*models.TimeEntry, error)
+	FindRunning(userID string) (*models.TimeEntry, error)
+	FindByTodo(todoID string) ([]models.TimeEntry, error)
+	Update(entry *models.TimeEntry) error
+	Delete(id string) error
+	Report(query TimeReportQuery) ([]models.TimeReportRow, error)
+}
+
+type timeEntryRepository struct {
+	db *gorm.DB
+}
+
+func NewTimeEntryRepository(db *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepository{db: db}
+}
+
+func (r *timeEntryRepository) Create(entry *models.TimeEntry) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *timeEntryRepository) FindByID(id string) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	if err := r.db.First(&entry, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindRunning returns the user's running timer or gorm.ErrRecordNotFound
+func (r *timeEntryRepository) FindRunning(userID string) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	if err := r.db.Where("user_id = ? AND ended_at IS NULL", userID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindByTodo returns the todo's entries, newest first
+func (r *timeEntryRepository) FindByTodo(todoID string) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	if err := r.db.Where("todo_id = ?", todoID).Order("started_at DESC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *timeEntryRepository) Update(entry *models.TimeEntry) error {
+	return r.db.Save(entry).Error
+}
+
+func (r *timeEntryRepository) Delete(id string) error {
+	return r.db.Delete(&models.TimeEntry{}, "id = ?", id).Error
+}
+
+// Report sums tracked time per todo, list or day. Running timers count up to now,
+// and an entry belongs to the day (in query.Location) on which it started.
+func (r *timeEntryRepository) Report(query TimeReportQuery) ([]models.TimeReportRow, error) {
+	loc := query.Location
+	if loc == "" {
+		loc = "UTC"
+	}
+
+	seconds := "COALESCE(SUM(CASE WHEN time_entries.ended_at IS NULL " +
+		"THEN EXTRACT(EPOCH FROM NOW() - time_entries.started_at) " +
+		"ELSE time_entries.duration END), 0)::bigint AS seconds"
+
+	db := r.db.Table("time_entries").
+		Joins("LEFT JOIN todos ON todos.id = time_entries.todo_id").
+		Where("todos.deleted_at IS NULL")
+
+	switch query.GroupBy {
+	case TimeReportByTodo:
+		db = db.Select("time_entries.todo_id AS key, COALESCE(MAX(todos.title), '') AS label, " + seconds + ", COUNT(*) AS entries").
+			Group("time_entries.todo_id").
+			Order("seconds DESC")
+	case TimeReportByList:
+		db = db.Joins("LEFT JOIN lists ON lists.id = todos.list_id").
+			Select("COALESCE(todos.list_id::text, '') AS key, COALESCE(MAX(lists.name), '') AS label, " + seconds + ", COUNT(*) AS entries").
+			Group("todos.list_id").
+			Order("seconds DESC")
+	case TimeReportByDay:
+		day := "to_char(time_entries.started_at AT TIME ZONE ?, 'YYYY-MM-DD')"
+		db = db.Select(day+" AS key, '' AS label, "+seconds+", COUNT(*) AS entries", loc).
+			Group("key").
+			Order("key ASC")
+	default:
+		return nil, fmt.Errorf("unknown report grouping: %s", query.GroupBy)
+	}
+
+	if query.UserID != "" {
+		db = db.Where("time_entries.user_id = ?", query.UserID)
+	}
+	if query.TodoID != "" {
+		db = db.Where("time_entries.todo_id = ?", query.TodoID)
+	}
+	if query.ListID != nil {
+		if *query.ListID == "" {
+			db = db.Where("todos.list_id IS NULL")
+		} else {
+			db = db.Where("todos.list_id = ?", *query.ListID)
+		}
+	}
+	if query.From != nil {
+		db = db.Where("time_entries.started_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("time_entries.started_at < ?", *query.To)
+	}
+
+	rows := []models.TimeReportRow{}
+	if err := db.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
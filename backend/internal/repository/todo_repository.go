@@ -1,17 +1,152 @@
 package repository
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testbox/internal/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Due date windows supported by TodoQuery.Due
+const (
+	DueOverdue  = "overdue"
+	DueToday    = "today"
+	DueThisWeek = "week"
+)
+
+// Archive filters supported by TodoQuery.Archived
+const (
+	ArchivedExclude = ""        // hide archived todos (default)
+	ArchivedInclude = "include" // archived and active todos
+	ArchivedOnly    = "only"    // archived todos only
+)
+
+// PositionGap is the spacing between manual positions after a rebalance
+// and between a new todo and the current top of the list
+const PositionGap = 1024.0
+
+// MinPositionGap is the smallest gap between neighbours before positions are rebalanced
+const MinPositionGap = 1e-6
+
+// Page size limits for TodoQuery.Limit
+const (
+	DefaultTodoPageSize = 50
+	MaxTodoPageSize     = 200
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+// or does not belong to the requested sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// sortColumn describes a sortable column and the Go type of its cursor value
+type sortColumn struct {
+	column   string
+	kind     string // "time", "int", "float" or "string"
+	nullable bool
+}
+
+// todoSortColumns maps public sort keys to their columns
+var todoSortColumns = map[string]sortColumn{
+	"created_at":   {column: "created_at", kind: "time"},
+	"updated_at":   {column: "updated_at", kind: "time"},
+	"due_date":     {column: "due_date", kind: "time", nullable: true},
+	"start_date":   {column: "start_date", kind: "time", nullable: true},
+	"priority":     {column: "priority", kind: "int"},
+	"title":        {column: "title", kind: "string"},
+	"position":     {column: "position", kind: "float"},
+	"completed_at": {column: "completed_at", kind: "time", nullable: true},
+	"archived_at":  {column: "archived_at", kind: "time", nullable: true},
+}
+
+// TodoQuery describes filtering, sorting and pagination options for listing todos
+type TodoQuery struct {
+	Due         string     // "overdue", "today" or "week"
+	Priority    *int       // exact priority level
+	Completed   *bool      // completion state
+	Status      string     // workflow status
+	Archived    string     // ArchivedExclude, ArchivedInclude or ArchivedOnly
+	ParentID    *string    // direct children of the given todo
+	ListID      *string    // todos in the given list; "" selects todos without a list
+	Tags        []string   // normalized tag names; todos must carry all of them
+	RootOnly    bool       // only todos without a parent
+	Search      string     // case-insensitive match on title and content
+	CreatedFrom *time.Time // created_at >= CreatedFrom
+	CreatedTo   *time.Time // created_at < CreatedTo
+	DueFrom     *time.Time // due_date >= DueFrom
+	DueTo       *time.Time // due_date < DueTo
+	DoneFrom    *time.Time // completed_at >= DoneFrom
+	DoneTo      *time.Time // completed_at < DoneTo
+	Now         time.Time  // reference time for Due windows (zero: time.Now()); its location decides where days start
+	SortBy      string     // one of the keys in todoSortColumns (default "position")
+	Order       string     // "asc" or "desc"
+	Cursor      string     // opaque token from a previous TodoPage.NextCursor
+	Limit       int        // page size, capped at MaxTodoPageSize
+}
+
+// TodoPage is a single page of todos with the cursor for the next page
+type TodoPage struct {
+	Items      []models.Todo `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	Total      int64         `json:"total"`
+}
+
+// TodoBulkChange is a set of changes that ApplyBulk writes in a single transaction
+type TodoBulkChange struct {
+	Update     []*models.Todo // todos whose own columns changed; tags are not saved
+	DeleteIDs  []string       // moved to the trash with one shared deleted_at
+	MoveIDs    []string       // assigned to ListID
+	ListID     *string
+	TagID      string // tag linked to every todo in TagTodoIDs
+	TagTodoIDs []string
+}
+
+// todoCursor is the decoded form of a pagination token
+type todoCursor struct {
+	SortBy string          `json:"s"`
+	Order  string          `json:"o"`
+	Value  json.RawMessage `json:"v"`
+	ID     string          `json:"id"`
+}
+
+// IsValidTodoSort reports whether key can be used as TodoQuery.SortBy
+func IsValidTodoSort(key string) bool {
+	_, ok := todoSortColumns[key]
+	return ok
+}
+
 type TodoRepository interface {
 	Create(todo *models.Todo) error
 	FindByID(id string) (*models.Todo, error)
-	FindAll() ([]models.Todo, error)
+	FindPage(query TodoQuery) (*TodoPage, error)
+	FindAll(query TodoQuery) ([]models.Todo, error)
+	Count(query TodoQuery) (int64, error)
+	FindChildren(parentID string) ([]models.Todo, error)
+	FindSubtree(rootID string) ([]models.Todo, error)
+	OccurrenceExists(seriesID string, occurrence int) (bool, error)
+	FindOccurrence(seriesID string, occurrence int) (*models.Todo, error)
 	Update(todo *models.Todo) error
+	ReplaceTags(todo *models.Todo, tags []models.Tag) error
 	Delete(id string) error
+	DeleteByIDs(ids []string) error
+	SetArchived(ids []string, archivedAt *time.Time) error
+	FindArchivable(completedBefore time.Time, limit int) ([]string, error)
+	FindTrash() ([]models.Todo, error)
+	FindTrashedSubtree(rootID string) ([]models.Todo, error)
+	Restore(ids []string) error
+	FindDeletedBefore(cutoff time.Time, limit int) ([]string, error)
+	PurgeByIDs(ids []string) error
+	MoveToList(ids []string, listID *string) error
+	FindNeighbor(position float64, before bool) (*models.Todo, error)
+	FindByIDs(ids []string) ([]models.Todo, error)
+	UpdatePositions(positions map[string]float64) error
+	RebalancePositions() error
+	ApplyBulk(change TodoBulkChange) error
 }
 
 type todoRepository struct {
@@ -22,30 +157,616 @@ func NewTodoRepository(db *gorm.DB) TodoRepository {
 	return &todoRepository{db: db}
 }
 
+// Create inserts the todo; unless a position is given it is placed at the top of the manual order.
+// Top positions are handed out one at a time under an advisory lock so concurrent creates never tie.
 func (r *todoRepository) Create(todo *models.Todo) error {
-	return r.db.Create(todo).Error
+	if todo.Position != 0 {
+		return r.db.Create(todo).Error
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "todos:position").Error; err != nil {
+			return err
+		}
+
+		var top float64
+		if err := tx.Model(&models.Todo{}).Select("COALESCE(MIN(position), 0)").Scan(&top).Error; err != nil {
+			return err
+		}
+		todo.Position = top - PositionGap
+		return tx.Create(todo).Error
+	})
 }
 
 func (r *todoRepository) FindByID(id string) (*models.Todo, error) {
 	var todo models.Todo
-	if err := r.db.First(&todo, "id = ?", id).Error; err != nil {
+	if err := r.db.Preload("Tags").First(&todo, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &todo, nil
 }
 
-func (r *todoRepository) FindAll() ([]models.Todo, error) {
+// FindPage returns one page of todos using keyset pagination on (sort column, id)
+func (r *todoRepository) FindPage(query TodoQuery) (*TodoPage, error) {
+	filtered, err := applyTodoFilters(r.db.Model(&models.Todo{}), query, query.now())
+	if err != nil {
+		return nil, err
+	}
+	// 카운트와 페이지 조회에서 같은 조건을 재사용합니다
+	filtered = filtered.Session(&gorm.Session{})
+
+	var total int64
+	if err := filtered.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	col, ok := todoSortColumns[query.SortBy]
+	if !ok {
+		query.SortBy = "position"
+		query.Order = "asc"
+		col = todoSortColumns[query.SortBy]
+	}
+	if query.Order != "asc" {
+		query.Order = "desc"
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultTodoPageSize
+	}
+	if limit > MaxTodoPageSize {
+		limit = MaxTodoPageSize
+	}
+
+	expr := col.expr(query.Order)
+	db := filtered
+	if query.Cursor != "" {
+		db, err = applyTodoCursor(db, query, col, expr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	direction := "DESC"
+	if query.Order == "asc" {
+		direction = "ASC"
+	}
+
 	var todos []models.Todo
-	if err := r.db.Order("created_at DESC").Find(&todos).Error; err != nil {
+	if err := db.Preload("Tags").
+		Order(fmt.Sprintf("%s %s, id %s", expr, direction, direction)).
+		Limit(limit + 1).
+		Find(&todos).Error; err != nil {
+		return nil, err
+	}
+
+	page := &TodoPage{Items: todos, Total: total}
+	if len(todos) > limit {
+		page.Items = todos[:limit]
+		last := page.Items[limit-1]
+		cursor, err := encodeTodoCursor(query, &last)
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = cursor
+	}
+	return page, nil
+}
+
+// FindAll returns every todo matching the query's filters in manual order, ignoring paging options
+func (r *todoRepository) FindAll(query TodoQuery) ([]models.Todo, error) {
+	db, err := applyTodoFilters(r.db.Model(&models.Todo{}), query, query.now())
+	if err != nil {
+		return nil, err
+	}
+
+	var todos []models.Todo
+	if err := db.Preload("Tags").Order("position ASC, id ASC").Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// Count returns the number of todos matching the query's filters
+func (r *todoRepository) Count(query TodoQuery) (int64, error) {
+	db, err := applyTodoFilters(r.db.Model(&models.Todo{}), query, query.now())
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *todoRepository) FindChildren(parentID string) ([]models.Todo, error) {
+	var todos []models.Todo
+	if err := r.db.Preload("Tags").Where("parent_id = ?", parentID).Order("created_at ASC").Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// FindSubtree returns the todo with the given ID and all of its descendants
+func (r *todoRepository) FindSubtree(rootID string) ([]models.Todo, error) {
+	var ids []string
+	err := r.db.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM todos WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id FROM todos t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+		)
+		SELECT id FROM subtree`, rootID).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []models.Todo{}, nil
+	}
+
+	var todos []models.Todo
+	if err := r.db.Preload("Tags").Where("id IN ?", ids).Order("created_at ASC").Find(&todos).Error; err != nil {
 		return nil, err
 	}
 	return todos, nil
 }
 
+// OccurrenceExists reports whether the given occurrence of a recurring series was already generated.
+// Occurrences in the trash count, so deleting one does not make it come back.
+func (r *todoRepository) OccurrenceExists(seriesID string, occurrence int) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Todo{}).
+		Where("series_id = ? AND occurrence = ?", seriesID, occurrence).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindOccurrence returns the given occurrence of a recurring series, unless it is in the trash
+func (r *todoRepository) FindOccurrence(seriesID string, occurrence int) (*models.Todo, error) {
+	var todo models.Todo
+	if err := r.db.First(&todo, "series_id = ? AND occurrence = ?", seriesID, occurrence).Error; err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// Update saves the todo's own columns; tags are changed through ReplaceTags
 func (r *todoRepository) Update(todo *models.Todo) error {
-	return r.db.Save(todo).Error
+	return r.db.Omit(clause.Associations).Save(todo).Error
+}
+
+// ReplaceTags sets the todo's tags to exactly the given tags
+func (r *todoRepository) ReplaceTags(todo *models.Todo, tags []models.Tag) error {
+	return r.db.Model(todo).Association("Tags").Replace(tags)
 }
 
 func (r *todoRepository) Delete(id string) error {
-	return r.db.Delete(&models.Todo{}, "id = ?", id).Error
+	return r.DeleteByIDs([]string{id})
+}
+
+// DeleteByIDs moves the todos to the trash. They are soft-deleted in one statement,
+// so a deleted subtree shares the same deleted_at and can be restored as a unit.
+// Tag links, dependencies, time entries, comments and activity are kept until the purge.
+func (r *todoRepository) DeleteByIDs(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Delete(&models.Todo{}, "id IN ?", ids).Error
+}
+
+// SetArchived archives the todos (or unarchives them when archivedAt is nil)
+func (r *todoRepository) SetArchived(ids []string, archivedAt *time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Todo{}).Where("id IN ?", ids).Update("archived_at", archivedAt).Error
+}
+
+// FindArchivable returns up to limit IDs of active root todos completed before the given time.
+// Subtasks are left out; they are archived together with their root.
+func (r *todoRepository) FindArchivable(completedBefore time.Time, limit int) ([]string, error) {
+	var ids []string
+	err := r.db.Model(&models.Todo{}).
+		Where("parent_id IS NULL AND completed = ? AND archived_at IS NULL AND completed_at < ?", true, completedBefore).
+		Order("completed_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// FindTrash returns the todos in the trash, most recently deleted first.
+// Todos deleted together with their parent are left out; they come back with it.
+func (r *todoRepository) FindTrash() ([]models.Todo, error) {
+	var todos []models.Todo
+	err := r.db.Unscoped().Preload("Tags").
+		Where("deleted_at IS NOT NULL").
+		Where(`NOT EXISTS (
+			SELECT 1 FROM todos parent
+			WHERE parent.id = todos.parent_id AND parent.deleted_at = todos.deleted_at)`).
+		Order("deleted_at DESC").
+		Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// FindTrashedSubtree returns the deleted todo with the given ID and the descendants
+// that were deleted together with it (same deleted_at)
+func (r *todoRepository) FindTrashedSubtree(rootID string) ([]models.Todo, error) {
+	var ids []string
+	err := r.db.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id, deleted_at FROM todos WHERE id = ? AND deleted_at IS NOT NULL
+			UNION ALL
+			SELECT t.id, t.deleted_at FROM todos t
+			JOIN subtree s ON t.parent_id = s.id AND t.deleted_at = s.deleted_at
+		)
+		SELECT id FROM subtree`, rootID).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []models.Todo{}, nil
+	}
+
+	var todos []models.Todo
+	if err := r.db.Unscoped().Preload("Tags").Where("id IN ?", ids).Order("created_at ASC").Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// Restore takes the todos out of the trash
+func (r *todoRepository) Restore(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Unscoped().Model(&models.Todo{}).Where("id IN ?", ids).Update("deleted_at", nil).Error
+}
+
+// FindDeletedBefore returns up to limit IDs of todos that were moved to the trash before cutoff
+func (r *todoRepository) FindDeletedBefore(cutoff time.Time, limit int) ([]string, error) {
+	var ids []string
+	err := r.db.Unscoped().Model(&models.Todo{}).
+		Where("deleted_at < ?", cutoff).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// PurgeByIDs permanently removes the todos together with their tag links, dependencies,
+// time entries, comments, activity and revisions
+func (r *todoRepository) PurgeByIDs(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM todo_tags WHERE todo_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM todo_dependencies WHERE todo_id IN ? OR blocker_id IN ?", ids, ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM time_entries WHERE todo_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM comments WHERE todo_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM activities WHERE todo_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM revisions WHERE owner_type = ? AND owner_id IN ?", models.RevisionOwnerTodo, ids).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Todo{}, "id IN ?", ids).Error
+	})
+}
+
+// applyTodoFilters adds WHERE clauses for the given query relative to now
+func applyTodoFilters(db *gorm.DB, query TodoQuery, now time.Time) (*gorm.DB, error) {
+	if query.Priority != nil {
+		db = db.Where("priority = ?", *query.Priority)
+	}
+	if query.Completed != nil {
+		db = db.Where("completed = ?", *query.Completed)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+	switch query.Archived {
+	case ArchivedExclude:
+		db = db.Where("archived_at IS NULL")
+	case ArchivedInclude:
+	case ArchivedOnly:
+		db = db.Where("archived_at IS NOT NULL")
+	default:
+		return nil, fmt.Errorf("unknown archive filter: %s", query.Archived)
+	}
+	if query.ParentID != nil {
+		db = db.Where("parent_id = ?", *query.ParentID)
+	}
+	if query.ListID != nil {
+		if *query.ListID == "" {
+			db = db.Where("list_id IS NULL")
+		} else {
+			db = db.Where("list_id = ?", *query.ListID)
+		}
+	}
+	if len(query.Tags) > 0 {
+		db = db.Where(`id IN (
+			SELECT todo_tags.todo_id FROM todo_tags
+			JOIN tags ON tags.id = todo_tags.tag_id
+			WHERE tags.name IN ?
+			GROUP BY todo_tags.todo_id
+			HAVING COUNT(DISTINCT tags.id) = ?)`, query.Tags, len(query.Tags))
+	}
+	if query.RootOnly {
+		db = db.Where("parent_id IS NULL")
+	}
+	if query.Search != "" {
+		pattern := "%" + escapeLike(query.Search) + "%"
+		db = db.Where("(title ILIKE ? OR content ILIKE ?)", pattern, pattern)
+	}
+	if query.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		db = db.Where("created_at < ?", *query.CreatedTo)
+	}
+	if query.DueFrom != nil {
+		db = db.Where("due_date >= ?", *query.DueFrom)
+	}
+	if query.DueTo != nil {
+		db = db.Where("due_date < ?", *query.DueTo)
+	}
+	if query.DoneFrom != nil {
+		db = db.Where("completed_at >= ?", *query.DoneFrom)
+	}
+	if query.DoneTo != nil {
+		db = db.Where("completed_at < ?", *query.DoneTo)
+	}
+
+	startOfDay := StartOfDay(now)
+	switch query.Due {
+	case "":
+	case DueOverdue:
+		db = db.Where("due_date < ? AND completed = ?", now, false)
+	case DueToday:
+		db = db.Where("due_date >= ? AND due_date < ?", startOfDay, startOfDay.AddDate(0, 0, 1))
+	case DueThisWeek:
+		startOfWeek := StartOfWeek(now)
+		db = db.Where("due_date >= ? AND due_date < ?", startOfWeek, startOfWeek.AddDate(0, 0, 7))
+	default:
+		return nil, fmt.Errorf("unknown due filter: %s", query.Due)
+	}
+
+	return db, nil
+}
+
+// now returns the reference time of the query
+func (q TodoQuery) now() time.Time {
+	if q.Now.IsZero() {
+		return time.Now()
+	}
+	return q.Now
+}
+
+// StartOfDay returns midnight of t's day in t's location
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns midnight of the Monday of t's week in t's location
+func StartOfWeek(t time.Time) time.Time {
+	startOfDay := StartOfDay(t)
+	offset := (int(startOfDay.Weekday()) + 6) % 7
+	return startOfDay.AddDate(0, 0, -offset)
+}
+
+// expr returns the ORDER BY expression; NULL dates sort last in either direction
+func (c sortColumn) expr(order string) string {
+	if !c.nullable {
+		return c.column
+	}
+	if order == "asc" {
+		return fmt.Sprintf("COALESCE(%s, 'infinity'::timestamptz)", c.column)
+	}
+	return fmt.Sprintf("COALESCE(%s, '-infinity'::timestamptz)", c.column)
+}
+
+// applyTodoCursor restricts the query to rows strictly after the cursor position
+func applyTodoCursor(db *gorm.DB, query TodoQuery, col sortColumn, expr string) (*gorm.DB, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(query.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor todoCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.SortBy != query.SortBy || cursor.Order != query.Order {
+		return nil, ErrInvalidCursor
+	}
+
+	op := "<"
+	if query.Order == "asc" {
+		op = ">"
+	}
+
+	if string(cursor.Value) == "null" {
+		if !col.nullable {
+			return nil, ErrInvalidCursor
+		}
+		sentinel := "'-infinity'::timestamptz"
+		if query.Order == "asc" {
+			sentinel = "'infinity'::timestamptz"
+		}
+		return db.Where(fmt.Sprintf("(%s, id) %s (%s, ?)", expr, op, sentinel), cursor.ID), nil
+	}
+
+	var value interface{}
+	switch col.kind {
+	case "time":
+		var t time.Time
+		err = json.Unmarshal(cursor.Value, &t)
+		value = t
+	case "int":
+		var n int64
+		err = json.Unmarshal(cursor.Value, &n)
+		value = n
+	case "float":
+		var f float64
+		err = json.Unmarshal(cursor.Value, &f)
+		value = f
+	default:
+		var s string
+		err = json.Unmarshal(cursor.Value, &s)
+		value = s
+	}
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return db.Where(fmt.Sprintf("(%s, id) %s (?, ?)", expr, op), value, cursor.ID), nil
+}
+
+// encodeTodoCursor builds the token pointing just past the given todo
+func encodeTodoCursor(query TodoQuery, todo *models.Todo) (string, error) {
+	value, err := json.Marshal(todoSortValue(todo, query.SortBy))
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := json.Marshal(todoCursor{
+		SortBy: query.SortBy,
+		Order:  query.Order,
+		Value:  value,
+		ID:     todo.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// todoSortValue returns the value of the sort column for the given todo
+func todoSortValue(todo *models.Todo, sortBy string) interface{} {
+	switch sortBy {
+	case "updated_at":
+		return todo.UpdatedAt
+	case "due_date":
+		return todo.DueDate
+	case "start_date":
+		return todo.StartDate
+	case "priority":
+		return todo.Priority
+	case "title":
+		return todo.Title
+	case "position":
+		return todo.Position
+	case "completed_at":
+		return todo.CompletedAt
+	case "archived_at":
+		return todo.ArchivedAt
+	default:
+		return todo.CreatedAt
+	}
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// MoveToList assigns the given todos to a list (nil moves them to the inbox)
+func (r *todoRepository) MoveToList(ids []string, listID *string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Todo{}).Where("id IN ?", ids).Update("list_id", listID).Error
+}
+
+// FindNeighbor returns the todo directly before (or after) the given position in the manual order
+func (r *todoRepository) FindNeighbor(position float64, before bool) (*models.Todo, error) {
+	db := r.db.Model(&models.Todo{})
+	if before {
+		db = db.Where("position < ?", position).Order("position DESC")
+	} else {
+		db = db.Where("position > ?", position).Order("position ASC")
+	}
+
+	var todo models.Todo
+	if err := db.First(&todo).Error; err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+func (r *todoRepository) FindByIDs(ids []string) ([]models.Todo, error) {
+	var todos []models.Todo
+	if len(ids) == 0 {
+		return todos, nil
+	}
+	if err := r.db.Preload("Tags").Where("id IN ?", ids).Order("position ASC").Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// UpdatePositions writes several positions in one transaction
+func (r *todoRepository) UpdatePositions(positions map[string]float64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for id, position := range positions {
+			if err := tx.Model(&models.Todo{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ApplyBulk writes all parts of the change in one transaction; nothing is written if any part fails
+func (r *todoRepository) ApplyBulk(change TodoBulkChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, todo := range change.Update {
+			if err := tx.Omit(clause.Associations).Save(todo).Error; err != nil {
+				return err
+			}
+		}
+		if len(change.DeleteIDs) > 0 {
+			if err := tx.Delete(&models.Todo{}, "id IN ?", change.DeleteIDs).Error; err != nil {
+				return err
+			}
+		}
+		if len(change.MoveIDs) > 0 {
+			if err := tx.Model(&models.Todo{}).Where("id IN ?", change.MoveIDs).Update("list_id", change.ListID).Error; err != nil {
+				return err
+			}
+		}
+		if len(change.TagTodoIDs) > 0 {
+			err := tx.Exec(`
+				INSERT INTO todo_tags (todo_id, tag_id)
+				SELECT id, ? FROM todos WHERE id IN ?
+				ON CONFLICT DO NOTHING`, change.TagID, change.TagTodoIDs).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RebalancePositions renumbers all todos with PositionGap spacing, keeping their order.
+// Ties are broken by id like the list queries, so todos sharing a position keep the order shown.
+// Only needed when repeated midpoint inserts have exhausted float precision.
+func (r *todoRepository) RebalancePositions() error {
+	return r.db.Exec(`
+		UPDATE todos SET position = ranked.rn * ?
+		FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY position ASC, id ASC) AS rn FROM todos) ranked
+		WHERE todos.id = ranked.id`, PositionGap).Error
 }
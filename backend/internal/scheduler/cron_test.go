@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronRejectsUnsatisfiable(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{expr: "0 0 31 2 *", wantErr: true},
+		{expr: "0 0 30,31 2 *", wantErr: true},
+		{expr: "0 0 31 4,6,9,11 *", wantErr: true},
+		{expr: "0 0 29 2 *"},
+		{expr: "0 0 31 2,3 *"},
+		{expr: "0 0 31 2 1"}, // day of month or Monday
+		{expr: "@monthly"},
+	}
+
+	for _, tt := range tests {
+		spec, err := ParseCron(tt.expr)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseCron(%q) succeeded, want error", tt.expr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", tt.expr, err)
+			continue
+		}
+		from := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+		if next := spec.Next(from); next.IsZero() {
+			t.Errorf("ParseCron(%q).Next(%s) is zero", tt.expr, from)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+	"time"
+)
+
+const (
+	// claimBatchSize is the maximum number of jobs picked up per poll
+	claimBatchSize = 20
+	// jobLease is how long a claimed job stays locked before another poll may retry it
+	jobLease = 5 * time.Minute
+	// maxAttempts is how many times a failing one-off job is retried
+	maxAttempts = 5
+)
+
+// Handler runs a single job. Returning an error schedules a retry.
+type Handler func(ctx context.Context, job *models.ScheduledJob) error
+
+// Scheduler polls PostgreSQL for due jobs and runs them in-process
+type Scheduler struct {
+	repo     repository.JobRepository
+	interval time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+func NewScheduler(repo repository.JobRepository, interval time.Duration) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		repo:     repo,
+		interval: interval,
+		handlers: make(map[string]Handler),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Register binds a handler to a job kind. Call before Start.
+func (s *Scheduler) Register(kind string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[kind] = handler
+}
+
+// ScheduleAt creates or replaces the one-off job identified by key
+func (s *Scheduler) ScheduleAt(key, kind string, runAt time.Time, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	return s.repo.Upsert(&models.ScheduledJob{
+		Key:     key,
+		Kind:    kind,
+		Payload: string(data),
+		RunAt:   runAt,
+	})
+}
+
+// ScheduleCron creates or replaces the recurring job identified by key
+func (s *Scheduler) ScheduleCron(key, kind, spec string, payload interface{}) error {
+	cron, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	runAt := cron.Next(time.Now())
+	if runAt.IsZero() {
+		return fmt.Errorf("cron expression has no next run: %q", spec)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	return s.repo.Upsert(&models.ScheduledJob{
+		Key:      key,
+		Kind:     kind,
+		Payload:  string(data),
+		CronSpec: spec,
+		RunAt:    runAt,
+	})
+}
+
+// Cancel removes the job identified by key if it exists
+func (s *Scheduler) Cancel(key string) error {
+	return s.repo.DeleteByKey(key)
+}
+
+// Start launches the polling loop in the background
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.loop()
+	log.Printf("✓ Scheduler started (poll interval: %s)", s.interval)
+}
+
+// Stop stops polling and waits for running jobs until ctx is done.
+// Jobs interrupted by the deadline keep their lease and are retried after it expires.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Println("✓ Scheduler stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduler stop timed out: %w", ctx.Err())
+	}
+}
+
+// DecodePayload unmarshals the JSON payload of a job into v
+func DecodePayload(job *models.ScheduledJob, v interface{}) error {
+	if job.Payload == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(job.Payload), v)
+}
+
+func (s *Scheduler) loop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.poll()
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll claims due jobs and runs them one by one
+func (s *Scheduler) poll() {
+	jobs, err := s.repo.ClaimDue(time.Now(), claimBatchSize, jobLease)
+	if err != nil {
+		log.Printf("경고: 예약 작업 조회 실패: %v", err)
+		return
+	}
+
+	for i := range jobs {
+		if s.ctx.Err() != nil {
+			return
+		}
+		s.run(&jobs[i])
+	}
+}
+
+func (s *Scheduler) run(job *models.ScheduledJob) {
+	s.mu.RLock()
+	handler, ok := s.handlers[job.Kind]
+	s.mu.RUnlock()
+
+	var err error
+	if !ok {
+		err = fmt.Errorf("no handler registered for job kind %q", job.Kind)
+	} else {
+		err = safeRun(s.ctx, handler, job)
+	}
+
+	now := time.Now()
+	switch {
+	case job.CronSpec != "":
+		// 반복 작업은 실패 여부와 관계없이 다음 실행 시각으로 이동합니다
+		job.Status = models.JobPending
+		job.Attempts = 0
+		job.LastError = errorString(err)
+		var next time.Time
+		cron, parseErr := ParseCron(job.CronSpec)
+		if parseErr == nil {
+			next = cron.Next(now)
+		}
+		switch {
+		case parseErr != nil:
+			job.Status = models.JobFailed
+			job.LastError = parseErr.Error()
+		case next.IsZero():
+			// 다음 실행 시각이 없으면 매 폴링마다 다시 실행되지 않도록 멈춥니다
+			job.Status = models.JobFailed
+			job.LastError = fmt.Sprintf("cron expression has no next run: %q", job.CronSpec)
+		default:
+			job.RunAt = next
+		}
+	case err == nil:
+		job.Status = models.JobDone
+		job.LastError = ""
+	default:
+		job.Attempts++
+		job.LastError = err.Error()
+		if job.Attempts >= maxAttempts {
+			job.Status = models.JobFailed
+		} else {
+			// 지수 백오프: 30초, 1분, 2분, 4분...
+			job.Status = models.JobPending
+			job.RunAt = now.Add(time.Duration(1<<uint(job.Attempts-1)) * 30 * time.Second)
+		}
+	}
+
+	if err != nil {
+		log.Printf("경고: 예약 작업 실패 (%s, %s): %v", job.Kind, job.Key, err)
+	} else {
+		log.Printf("✓ 예약 작업 실행 완료: %s (%s)", job.Kind, job.Key)
+	}
+
+	if err := s.repo.Finish(job); err != nil {
+		log.Printf("경고: 예약 작업 상태 저장 실패: %v", err)
+	}
+}
+
+// safeRun converts a handler panic into an error so one job cannot stop the loop
+func safeRun(ctx context.Context, handler Handler, job *models.ScheduledJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,436 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"testbox/internal/messaging"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Fields of a document, weighted like the A/B/C weights of the PostgreSQL backend
+const (
+	fieldTitle = iota
+	fieldTags
+	fieldContent
+	fieldCount
+)
+
+var fieldWeights = [fieldCount]float64{1.0, 0.4, 0.1}
+
+// prefixWeight scales matches where the query word is only a prefix of the indexed word,
+// e.g. "회의" in "회의를" or "deploy" in "deployment"
+const prefixWeight = 0.5
+
+// snippetWords is the length of a content fragment; at most snippetFragments are shown
+const (
+	snippetWords     = 20
+	snippetFragments = 2
+)
+
+// document is an indexed todo or blog post
+type document struct {
+	key       string
+	kind      string
+	id        string
+	title     string
+	content   string
+	updatedAt time.Time
+	fields    [fieldCount][]token
+	published bool // todos always; blog posts in the published status
+}
+
+// MemoryIndex is an in-process inverted index over todos and blog posts.
+// It is built from the database on startup and kept current by HandleEvent, so search
+// works without PostgreSQL extensions. Results are eventually consistent with the events.
+//
+// Queries follow the web search syntax of the PostgreSQL backend: words must all match,
+// "quoted phrases" must match consecutively, OR offers alternatives and -word excludes.
+// Words also match indexed words they are a prefix of, at a lower rank.
+type MemoryIndex struct {
+	todoRepo repository.TodoRepository
+	blogRepo repository.BlogRepository
+
+	mu       sync.RWMutex
+	docs     map[string]*document
+	postings map[string]map[string]bool // term → document keys
+	// keys changed while Rebuild was loading, reloaded once the new index is in place
+	rebuilding bool
+	pending    map[string]bool
+}
+
+func NewMemoryIndex(todoRepo repository.TodoRepository, blogRepo repository.BlogRepository) *MemoryIndex {
+	return &MemoryIndex{
+		todoRepo: todoRepo,
+		blogRepo: blogRepo,
+		docs:     map[string]*document{},
+		postings: map[string]map[string]bool{},
+	}
+}
+
+// Rebuild replaces the index with all todos (including archived ones) and blog posts
+// outside the trash
+func (x *MemoryIndex) Rebuild() error {
+	x.mu.Lock()
+	x.rebuilding = true
+	x.pending = map[string]bool{}
+	x.mu.Unlock()
+
+	docs, err := x.load()
+
+	x.mu.Lock()
+	pending := x.pending
+	x.rebuilding = false
+	x.pending = nil
+	if err == nil {
+		x.docs = map[string]*document{}
+		x.postings = map[string]map[string]bool{}
+		for _, doc := range docs {
+			x.put(doc)
+		}
+	}
+	x.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("검색 색인 생성 실패: %w", err)
+	}
+	for key := range pending {
+		x.reload(key)
+	}
+
+	log.Printf("✓ 검색 색인 생성 완료: %d개", len(docs))
+	return nil
+}
+
+func (x *MemoryIndex) load() ([]*document, error) {
+	todos, err := x.todoRepo.FindAll(repository.TodoQuery{Archived: repository.ArchivedInclude})
+	if err != nil {
+		return nil, err
+	}
+	blogs, err := x.blogRepo.FindAll(nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]*document, 0, len(todos)+len(blogs))
+	for i := range todos {
+		docs = append(docs, todoDocument(&todos[i]))
+	}
+	for i := range blogs {
+		docs = append(docs, blogDocument(&blogs[i]))
+	}
+	return docs, nil
+}
+
+// HandleEvent applies a todo event to the index. Changed items are reloaded from the
+// database rather than taken from the event, so every event shape carries enough information.
+func (x *MemoryIndex) HandleEvent(event messaging.TodoEvent) {
+	switch event.Action {
+	case "created", "updated", "restored", "status_changed", "moved", "archived", "unarchived":
+		x.reload(documentKey(models.SearchTypeTodo, event.TodoID))
+	case "deleted":
+		x.remove(documentKey(models.SearchTypeTodo, event.TodoID))
+	case "blog_created", "blog_updated", "blog_restored", "blog_status_changed", "blog_published":
+		x.reload(documentKey(models.SearchTypeBlog, event.TodoID))
+	case "blog_deleted":
+		x.remove(documentKey(models.SearchTypeBlog, event.TodoID))
+	case "tag_renamed", "tag_merged":
+		// 태그는 여러 문서에 걸쳐 있으므로 전체를 다시 색인합니다
+		if err := x.Rebuild(); err != nil {
+			log.Printf("경고: %v", err)
+		}
+	}
+}
+
+// reload indexes the current state of a document, or drops it if it no longer exists
+func (x *MemoryIndex) reload(key string) {
+	kind, id, _ := strings.Cut(key, ":")
+
+	var doc *document
+	var err error
+	switch kind {
+	case models.SearchTypeTodo:
+		var todo *models.Todo
+		if todo, err = x.todoRepo.FindByID(id); err == nil {
+			doc = todoDocument(todo)
+		}
+	case models.SearchTypeBlog:
+		var blog *models.BlogPost
+		if blog, err = x.blogRepo.FindByID(id); err == nil {
+			doc = blogDocument(blog)
+		}
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("경고: 검색 색인 갱신 실패 (%s): %v", key, err)
+		return
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.rebuilding {
+		x.pending[key] = true
+	}
+	x.drop(key)
+	if doc != nil {
+		x.put(doc)
+	}
+}
+
+func (x *MemoryIndex) remove(key string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.rebuilding {
+		x.pending[key] = true
+	}
+	x.drop(key)
+}
+
+// put adds a document; the caller holds the write lock
+func (x *MemoryIndex) put(doc *document) {
+	x.docs[doc.key] = doc
+	for _, tokens := range doc.fields {
+		for _, t := range tokens {
+			keys := x.postings[t.term]
+			if keys == nil {
+				keys = map[string]bool{}
+				x.postings[t.term] = keys
+			}
+			keys[doc.key] = true
+		}
+	}
+}
+
+// drop removes a document; the caller holds the write lock
+func (x *MemoryIndex) drop(key string) {
+	doc, ok := x.docs[key]
+	if !ok {
+		return
+	}
+	delete(x.docs, key)
+	for _, tokens := range doc.fields {
+		for _, t := range tokens {
+			if keys := x.postings[t.term]; keys != nil {
+				delete(keys, key)
+				if len(keys) == 0 {
+					delete(x.postings, t.term)
+				}
+			}
+		}
+	}
+}
+
+// Search ranks the indexed documents against the query
+func (x *MemoryIndex) Search(query repository.SearchQuery) (*repository.SearchPage, error) {
+	types := map[string]bool{}
+	for _, t := range query.Types {
+		if t != models.SearchTypeTodo && t != models.SearchTypeBlog {
+			return nil, fmt.Errorf("unknown search type: %s", t)
+		}
+		types[t] = true
+	}
+
+	parsed := parseQuery(query.Text)
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	type hit struct {
+		doc   *document
+		score float64
+	}
+	var hits []hit
+	if len(parsed.clauses) > 0 {
+		for _, doc := range x.candidates(parsed.clauses[0]) {
+			if len(types) > 0 && !types[doc.kind] {
+				continue
+			}
+			if query.PublishedOnly && !doc.published {
+				continue
+			}
+			if score, ok := x.score(doc, parsed); ok {
+				hits = append(hits, hit{doc, score})
+			}
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		if !hits[i].doc.updatedAt.Equal(hits[j].doc.updatedAt) {
+			return hits[i].doc.updatedAt.After(hits[j].doc.updatedAt)
+		}
+		return hits[i].doc.id < hits[j].doc.id
+	})
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = repository.DefaultSearchPageSize
+	}
+	if limit > repository.MaxSearchPageSize {
+		limit = repository.MaxSearchPageSize
+	}
+
+	page := &repository.SearchPage{Items: []models.SearchResult{}, Total: int64(len(hits))}
+	for i := query.Offset; i < len(hits) && i < query.Offset+limit; i++ {
+		doc := hits[i].doc
+		page.Items = append(page.Items, models.SearchResult{
+			Type:      doc.kind,
+			ID:        doc.id,
+			Title:     highlight(doc.title, doc.fields[fieldTitle], parsed, true),
+			Snippet:   highlight(doc.content, doc.fields[fieldContent], parsed, false),
+			Rank:      hits[i].score,
+			UpdatedAt: doc.updatedAt,
+		})
+	}
+	return page, nil
+}
+
+// candidates returns the documents containing any word of the clause; the caller holds the lock
+func (x *MemoryIndex) candidates(c clause) []*document {
+	keys := map[string]bool{}
+	for _, phrase := range c {
+		for term := range x.postings {
+			if strings.HasPrefix(term, phrase[0]) {
+				for key := range x.postings[term] {
+					keys[key] = true
+				}
+			}
+		}
+	}
+
+	docs := make([]*document, 0, len(keys))
+	for key := range keys {
+		docs = append(docs, x.docs[key])
+	}
+	return docs
+}
+
+// score checks that the document satisfies every clause and no exclusion and ranks it.
+// Each matched word adds its field weight, lowered for prefix matches, scaled by how rare
+// the word is across the index. The caller holds the lock.
+func (x *MemoryIndex) score(doc *document, q query) (float64, bool) {
+	for _, excluded := range q.excluded {
+		if _, ok := matchPhrase(doc, excluded); ok {
+			return 0, false
+		}
+	}
+
+	total := 0.0
+	for _, c := range q.clauses {
+		best, matched := 0.0, false
+		for _, phrase := range c {
+			weight, ok := matchPhrase(doc, phrase)
+			if !ok {
+				continue
+			}
+			matched = true
+			if weight *= x.rarity(phrase); weight > best {
+				best = weight
+			}
+		}
+		if !matched {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+// rarity is the inverse document frequency of the phrase's rarest word
+func (x *MemoryIndex) rarity(phrase []string) float64 {
+	rarest := 0.0
+	for _, word := range phrase {
+		count := len(x.postings[word])
+		if count == 0 {
+			count = 1
+		}
+		if idf := 1 + float64(len(x.docs))/float64(count); idf > rarest {
+			rarest = idf
+		}
+	}
+	return rarest
+}
+
+// matchPhrase reports whether the words of the phrase occur consecutively in a field of
+// the document, and their summed field weight
+func matchPhrase(doc *document, phrase []string) (float64, bool) {
+	weight, ok := 0.0, false
+	for field, tokens := range doc.fields {
+		for i := 0; i+len(phrase) <= len(tokens); i++ {
+			w := 0.0
+			for j, word := range phrase {
+				m := matchWord(tokens[i+j].term, word)
+				if m == 0 {
+					w = 0
+					break
+				}
+				w += m
+			}
+			if w > 0 {
+				weight += w * fieldWeights[field]
+				ok = true
+			}
+		}
+	}
+	return weight, ok
+}
+
+// matchWord returns 1 for an exact match, prefixWeight if word is a prefix of term, else 0
+func matchWord(term, word string) float64 {
+	switch {
+	case term == word:
+		return 1
+	case strings.HasPrefix(term, word):
+		return prefixWeight
+	}
+	return 0
+}
+
+func todoDocument(todo *models.Todo) *document {
+	doc := &document{
+		key:       documentKey(models.SearchTypeTodo, todo.ID),
+		kind:      models.SearchTypeTodo,
+		id:        todo.ID,
+		title:     todo.Title,
+		content:   todo.Content,
+		updatedAt: todo.UpdatedAt,
+		published: true,
+	}
+	doc.index(todo.Tags)
+	return doc
+}
+
+func blogDocument(blog *models.BlogPost) *document {
+	doc := &document{
+		key:       documentKey(models.SearchTypeBlog, blog.ID),
+		kind:      models.SearchTypeBlog,
+		id:        blog.ID,
+		title:     blog.Title,
+		content:   blog.Content,
+		updatedAt: blog.UpdatedAt,
+		published: blog.Status == models.BlogPublished,
+	}
+	doc.index(blog.Tags)
+	return doc
+}
+
+func (d *document) index(tags []models.Tag) {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	d.fields[fieldTitle] = tokenize(d.title)
+	d.fields[fieldTags] = tokenize(strings.Join(names, " "))
+	d.fields[fieldContent] = tokenize(d.content)
+}
+
+func documentKey(kind, id string) string {
+	return kind + ":" + id
+}
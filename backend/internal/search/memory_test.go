@@ -0,0 +1,100 @@
+package search
+
+import (
+	"testbox/internal/messaging"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// fakeTodoRepo serves the todos the index loads; other methods are not used
+type fakeTodoRepo struct {
+	repository.TodoRepository
+	todos map[string]*models.Todo
+}
+
+func (r *fakeTodoRepo) FindAll(query repository.TodoQuery) ([]models.Todo, error) {
+	var todos []models.Todo
+	for _, todo := range r.todos {
+		todos = append(todos, *todo)
+	}
+	return todos, nil
+}
+
+func (r *fakeTodoRepo) FindByID(id string) (*models.Todo, error) {
+	todo, ok := r.todos[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *todo
+	return &copied, nil
+}
+
+// fakeBlogRepo serves the blog posts the index loads; other methods are not used
+type fakeBlogRepo struct {
+	repository.BlogRepository
+	blogs map[string]*models.BlogPost
+}
+
+func (r *fakeBlogRepo) FindAll(tags []string, status string) ([]models.BlogPost, error) {
+	var blogs []models.BlogPost
+	for _, blog := range r.blogs {
+		blogs = append(blogs, *blog)
+	}
+	return blogs, nil
+}
+
+func (r *fakeBlogRepo) FindByID(id string) (*models.BlogPost, error) {
+	blog, ok := r.blogs[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *blog
+	return &copied, nil
+}
+
+func TestMemoryIndexFollowsBlogStatus(t *testing.T) {
+	post := &models.BlogPost{ID: "post-1", Title: "Release notes", Content: "What changed", Status: models.BlogDraft}
+	blogs := &fakeBlogRepo{blogs: map[string]*models.BlogPost{post.ID: post}}
+	index := NewMemoryIndex(&fakeTodoRepo{todos: map[string]*models.Todo{}}, blogs)
+	if err := index.Rebuild(); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+
+	search := func(publishedOnly bool) int64 {
+		t.Helper()
+		page, err := index.Search(repository.SearchQuery{Text: "release", PublishedOnly: publishedOnly})
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		return page.Total
+	}
+
+	tests := []struct {
+		name          string
+		action        string
+		status        string
+		wantPublished int64
+		wantAll       int64
+	}{
+		{"draft stays hidden", "", models.BlogDraft, 0, 1},
+		{"published by the scheduled job", "blog_published", models.BlogPublished, 1, 1},
+		{"moved back to draft", "blog_status_changed", models.BlogDraft, 0, 1},
+		{"published manually", "blog_status_changed", models.BlogPublished, 1, 1},
+		{"unlisted", "blog_status_changed", models.BlogUnlisted, 0, 1},
+	}
+	for _, tt := range tests {
+		post.Status = tt.status
+		if tt.action != "" {
+			index.HandleEvent(messaging.TodoEvent{Action: tt.action, TodoID: post.ID})
+		}
+		if got := search(true); got != tt.wantPublished {
+			t.Errorf("%s: published-only search found %d, want %d", tt.name, got, tt.wantPublished)
+		}
+		if got := search(false); got != tt.wantAll {
+			t.Errorf("%s: search found %d, want %d", tt.name, got, tt.wantAll)
+		}
+	}
+}
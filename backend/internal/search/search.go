@@ -0,0 +1,43 @@
+// Package search provides the full-text search backends behind GET /api/search.
+package search
+
+import (
+	"fmt"
+	"testbox/internal/config"
+	"testbox/internal/messaging"
+	"testbox/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// Backends supported by New
+const (
+	BackendPostgres = "postgres" // tsvector columns and GIN indexes maintained by the migrations
+	BackendMemory   = "memory"   // in-process inverted index fed by todo events
+)
+
+// Searcher ranks todos and blog posts against a query
+type Searcher interface {
+	Search(query repository.SearchQuery) (*repository.SearchPage, error)
+}
+
+// New creates the search backend selected by cfg.SearchBackend.
+// The memory backend is filled from the database and then follows the todo events.
+func New(cfg *config.Config, db *gorm.DB, todoRepo repository.TodoRepository, blogRepo repository.BlogRepository, rabbitmq *messaging.RabbitMQ) (Searcher, error) {
+	switch cfg.SearchBackend {
+	case BackendPostgres:
+		return repository.NewSearchRepository(db), nil
+	case BackendMemory:
+		index := NewMemoryIndex(todoRepo, blogRepo)
+		// 재색인 중 발생한 변경을 놓치지 않도록 먼저 구독합니다
+		if err := rabbitmq.Subscribe("search-index", index.HandleEvent); err != nil {
+			return nil, err
+		}
+		if err := index.Rebuild(); err != nil {
+			return nil, err
+		}
+		return index, nil
+	default:
+		return nil, fmt.Errorf("unknown search backend: %s", cfg.SearchBackend)
+	}
+}
@@ -0,0 +1,185 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a lowercased word and its byte range in the original text
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text into words of letters and digits. Hangul syllables are letters,
+// so Korean text is split at spaces and punctuation like English.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return tokens
+}
+
+// clause is a set of alternative phrases (joined by OR); one of them must match
+type clause [][]string
+
+// query is a parsed search text: every clause must match and no excluded phrase may
+type query struct {
+	clauses  []clause
+	excluded [][]string
+}
+
+// parseQuery reads web search syntax: words, "quoted phrases", OR between alternatives
+// and a leading - to exclude a word or phrase. Words split by punctuation form a phrase.
+func parseQuery(text string) query {
+	var q query
+	or := false
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if unicode.IsSpace(r) {
+			i += size
+			continue
+		}
+
+		negate := false
+		if r == '-' && i+1 < len(text) {
+			negate = true
+			i++
+		}
+
+		var raw string
+		quoted := i < len(text) && text[i] == '"'
+		if quoted {
+			end := strings.IndexByte(text[i+1:], '"')
+			if end < 0 {
+				raw, i = text[i+1:], len(text)
+			} else {
+				raw, i = text[i+1:i+1+end], i+2+end
+			}
+		} else {
+			end := strings.IndexFunc(text[i:], func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+			if end < 0 {
+				end = len(text) - i
+			}
+			raw, i = text[i:i+end], i+end
+		}
+
+		if raw == "OR" && !quoted && !negate {
+			or = len(q.clauses) > 0
+			continue
+		}
+
+		tokens := tokenize(raw)
+		if len(tokens) == 0 {
+			continue
+		}
+		phrase := make([]string, len(tokens))
+		for j, t := range tokens {
+			phrase[j] = t.term
+		}
+
+		switch {
+		case negate:
+			q.excluded = append(q.excluded, phrase)
+		case or:
+			last := len(q.clauses) - 1
+			q.clauses[last] = append(q.clauses[last], phrase)
+		default:
+			q.clauses = append(q.clauses, clause{phrase})
+		}
+		or = false
+	}
+	return q
+}
+
+// matches reports whether the token matches a word of the query's clauses
+func (q query) matches(t token) bool {
+	for _, c := range q.clauses {
+		for _, phrase := range c {
+			for _, word := range phrase {
+				if matchWord(t.term, word) > 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// highlight HTML-escapes text and wraps the words matching the query in <mark> tags.
+// Unless whole is set, only up to snippetFragments runs of snippetWords words around the
+// matches are returned, or the first run if nothing matched.
+func highlight(text string, tokens []token, q query, whole bool) string {
+	if whole {
+		return render(text, tokens, q, 0, len(text))
+	}
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	var fragments []string
+	covered := -1
+	for i, t := range tokens {
+		if i < covered || !q.matches(t) {
+			continue
+		}
+		from := i - snippetWords/4
+		if from < covered {
+			from = covered
+		}
+		if from < 0 {
+			from = 0
+		}
+		to := from + snippetWords
+		if to > len(tokens) {
+			to = len(tokens)
+		}
+		fragments = append(fragments, render(text, tokens, q, tokens[from].start, tokens[to-1].end))
+		covered = to
+		if len(fragments) == snippetFragments {
+			break
+		}
+	}
+	if len(fragments) == 0 {
+		to := snippetWords
+		if to > len(tokens) {
+			to = len(tokens)
+		}
+		return render(text, tokens, q, tokens[0].start, tokens[to-1].end)
+	}
+	return strings.Join(fragments, " … ")
+}
+
+// render escapes text[from:to] and marks the matching tokens inside it
+func render(text string, tokens []token, q query, from, to int) string {
+	var b strings.Builder
+	pos := from
+	for _, t := range tokens {
+		if t.start < from || t.end > to || !q.matches(t) {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:t.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[t.start:t.end]))
+		b.WriteString("</mark>")
+		pos = t.end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	return b.String()
+}
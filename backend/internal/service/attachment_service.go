@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"testbox/internal/messaging"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+	"testbox/internal/scheduler"
+	"testbox/internal/storage"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// JobAttachmentCleanup is the kind of the job that removes attachments of deleted todos and posts
+const JobAttachmentCleanup = "attachment_cleanup"
+
+// attachmentCleanupBatch is how many orphaned attachments one cleanup run removes
+const attachmentCleanupBatch = 500
+
+// ErrAttachmentNotFound is returned when the attachment does not exist
+var ErrAttachmentNotFound = errors.New("첨부 파일을 찾을 수 없습니다")
+
+// ErrAttachmentOwnerNotFound is returned when the todo or blog post to attach to does not exist
+var ErrAttachmentOwnerNotFound = errors.New("첨부 대상을 찾을 수 없습니다")
+
+// ErrAttachmentTooLarge is returned when the upload exceeds the configured size limit
+var ErrAttachmentTooLarge = errors.New("첨부 파일이 너무 큽니다")
+
+// ErrAttachmentTypeNotAllowed is returned when the detected MIME type is not allowed
+var ErrAttachmentTypeNotAllowed = errors.New("허용되지 않는 파일 형식입니다")
+
+// AttachmentLimits restricts what can be uploaded
+type AttachmentLimits struct {
+	MaxSize      int64
+	AllowedTypes []string // MIME types; "image/*" allows every image type
+}
+
+type AttachmentService interface {
+	Upload(ownerType, ownerID, uploadedBy, fileName string, body io.ReadSeeker, size int64) (*models.Attachment, error)
+	GetAttachments(ownerType, ownerID string) ([]models.Attachment, error)
+	GetAttachment(id string) (*models.Attachment, error)
+	Open(ctx context.Context, attachment *models.Attachment, offset, length int64) (io.ReadCloser, error)
+	DeleteAttachment(id string) error
+}
+
+type attachmentService struct {
+	repo     repository.AttachmentRepository
+	todoRepo repository.TodoRepository
+	blogRepo repository.BlogRepository
+	storage  storage.Storage
+	rabbitmq *messaging.RabbitMQ
+	limits   AttachmentLimits
+}
+
+func NewAttachmentService(repo repository.AttachmentRepository, todoRepo repository.TodoRepository, blogRepo repository.BlogRepository, store storage.Storage, rabbitmq *messaging.RabbitMQ, sched *scheduler.Scheduler, limits AttachmentLimits) AttachmentService {
+	s := &attachmentService{
+		repo:     repo,
+		todoRepo: todoRepo,
+		blogRepo: blogRepo,
+		storage:  store,
+		rabbitmq: rabbitmq,
+		limits:   limits,
+	}
+
+	// 삭제된 Todo/게시글의 첨부 파일은 매일 정리합니다
+	sched.Register(JobAttachmentCleanup, s.handleCleanup)
+	if err := sched.ScheduleCron(JobAttachmentCleanup, JobAttachmentCleanup, "@daily", nil); err != nil {
+		log.Printf("경고: 첨부 파일 정리 작업 예약 실패: %v", err)
+	}
+	return s
+}
+
+// Upload validates size and sniffed MIME type, stores the file and records it
+func (s *attachmentService) Upload(ownerType, ownerID, uploadedBy, fileName string, body io.ReadSeeker, size int64) (*models.Attachment, error) {
+	// 1. 첨부 대상 및 크기 확인
+	if err := s.ensureOwner(ownerType, ownerID); err != nil {
+		return nil, err
+	}
+	if size > s.limits.MaxSize {
+		return nil, fmt.Errorf("%w (최대 %d바이트)", ErrAttachmentTooLarge, s.limits.MaxSize)
+	}
+
+	// 2. 클라이언트가 보낸 Content-Type 대신 내용으로 형식을 판별합니다
+	contentType, err := sniffContentType(body)
+	if err != nil {
+		return nil, fmt.Errorf("파일 형식 확인 실패: %w", err)
+	}
+	if !s.isAllowedType(contentType) {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentTypeNotAllowed, contentType)
+	}
+
+	attachment := &models.Attachment{
+		ID:          uuid.New().String(),
+		OwnerType:   ownerType,
+		OwnerID:     ownerID,
+		FileName:    sanitizeFileName(fileName),
+		ContentType: contentType,
+		Size:        size,
+		UploadedBy:  uploadedBy,
+	}
+	attachment.StorageKey = path.Join("attachments", ownerType, ownerID, attachment.ID)
+
+	// 3. 저장소에 업로드 후 메타데이터 저장 (실패하면 업로드한 파일 삭제)
+	ctx := context.Background()
+	if err := s.storage.Put(ctx, attachment.StorageKey, body, size, contentType); err != nil {
+		return nil, fmt.Errorf("파일 저장 실패: %w", err)
+	}
+	if err := s.repo.Create(attachment); err != nil {
+		if delErr := s.storage.Delete(ctx, attachment.StorageKey); delErr != nil {
+			log.Printf("경고: 저장된 파일 삭제 실패: %v", delErr)
+		}
+		return nil, fmt.Errorf("첨부 파일 생성 실패: %w", err)
+	}
+
+	// 4. 이벤트 발행
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "attachment_added",
+		TodoID: ownerID,
+		Data:   attachment,
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ 첨부 파일 업로드 완료: %s (%s, %d바이트)", attachment.ID, contentType, size)
+	return attachment, nil
+}
+
+// GetAttachments lists the attachments of a todo or blog post
+func (s *attachmentService) GetAttachments(ownerType, ownerID string) ([]models.Attachment, error) {
+	if err := s.ensureOwner(ownerType, ownerID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.FindByOwner(ownerType, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("첨부 파일 조회 실패: %w", err)
+	}
+	return attachments, nil
+}
+
+// GetAttachment retrieves attachment metadata
+func (s *attachmentService) GetAttachment(id string) (*models.Attachment, error) {
+	attachment, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("첨부 파일 조회 실패: %w", err)
+	}
+	return attachment, nil
+}
+
+// Open streams the stored file; length < 0 reads to the end
+func (s *attachmentService) Open(ctx context.Context, attachment *models.Attachment, offset, length int64) (io.ReadCloser, error) {
+	reader, err := s.storage.Get(ctx, attachment.StorageKey, offset, length)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("파일 읽기 실패: %w", err)
+	}
+	return reader, nil
+}
+
+// DeleteAttachment removes the attachment record and its stored file
+func (s *attachmentService) DeleteAttachment(id string) error {
+	attachment, err := s.GetAttachment(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return fmt.Errorf("첨부 파일 삭제 실패: %w", err)
+	}
+	// 레코드가 지워졌으므로 파일 삭제 실패는 정리 작업에서 다시 시도하지 않습니다
+	if err := s.storage.Delete(context.Background(), attachment.StorageKey); err != nil {
+		log.Printf("경고: 저장된 파일 삭제 실패 (%s): %v", attachment.StorageKey, err)
+	}
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "attachment_deleted",
+		TodoID: attachment.OwnerID,
+		Data:   attachment,
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ 첨부 파일 삭제 완료: %s", id)
+	return nil
+}
+
+// handleCleanup removes attachments whose todo or blog post has been deleted
+func (s *attachmentService) handleCleanup(ctx context.Context, job *models.ScheduledJob) error {
+	orphans, err := s.repo.FindOrphans(attachmentCleanupBatch)
+	if err != nil {
+		return fmt.Errorf("고아 첨부 파일 조회 실패: %w", err)
+	}
+
+	for _, attachment := range orphans {
+		if err := s.storage.Delete(ctx, attachment.StorageKey); err != nil {
+			return fmt.Errorf("저장된 파일 삭제 실패 (%s): %w", attachment.StorageKey, err)
+		}
+		if err := s.repo.Delete(attachment.ID); err != nil {
+			return fmt.Errorf("첨부 파일 삭제 실패: %w", err)
+		}
+	}
+
+	if len(orphans) > 0 {
+		log.Printf("✓ 고아 첨부 파일 %d개 정리 완료", len(orphans))
+	}
+	return nil
+}
+
+// ensureOwner checks that the todo or blog post exists
+func (s *attachmentService) ensureOwner(ownerType, ownerID string) error {
+	var err error
+	switch ownerType {
+	case models.AttachmentOwnerTodo:
+		_, err = s.todoRepo.FindByID(ownerID)
+	case models.AttachmentOwnerBlog:
+		_, err = s.blogRepo.FindByID(ownerID)
+	default:
+		return fmt.Errorf("알 수 없는 첨부 대상: %s", ownerType)
+	}
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrAttachmentOwnerNotFound
+		}
+		return fmt.Errorf("첨부 대상 조회 실패: %w", err)
+	}
+	return nil
+}
+
+// isAllowedType matches the media type against the configured list ("image/*" style wildcards allowed)
+func (s *attachmentService) isAllowedType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range s.limits.AllowedTypes {
+		if allowed == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffContentType detects the MIME type from the first 512 bytes and rewinds the body
+func sniffContentType(body io.ReadSeeker) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+// sanitizeFileName keeps only the base name of an uploaded file
+func sanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	for len(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
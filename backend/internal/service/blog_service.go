@@ -1,50 +1,114 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"testbox/internal/cache"
 	"testbox/internal/messaging"
 	"testbox/internal/models"
 	"testbox/internal/repository"
+	"testbox/internal/scheduler"
+	"testbox/internal/slug"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// ErrBlogPostNotFound is returned when the blog post does not exist
+var ErrBlogPostNotFound = errors.New("블로그 포스트를 찾을 수 없습니다")
+
+// ErrBlogPostNotInTrash is returned when restoring a post that is not in the trash
+var ErrBlogPostNotInTrash = errors.New("휴지통에 없는 블로그 포스트입니다")
+
+// ErrInvalidBlogStatus is returned for a status other than draft, scheduled, published or unlisted
+var ErrInvalidBlogStatus = errors.New("블로그 포스트 상태는 draft, scheduled, published, unlisted 중 하나여야 합니다")
+
+// ErrInvalidPublishTime is returned when published_at does not fit the status:
+// scheduled posts need a future time and published or unlisted posts a past one
+var ErrInvalidPublishTime = errors.New("게시 시각이 상태와 맞지 않습니다")
+
+// JobBlogPublish is the kind of the job that publishes a scheduled blog post
+const JobBlogPublish = "blog_publish"
+
+// BlogPublication is the requested status of a post and, for scheduled posts, when it goes public.
+// An empty Status creates a published post.
+type BlogPublication struct {
+	Status      string
+	PublishedAt *time.Time
+}
+
+// publishPayload is the data stored with a JobBlogPublish job
+type publishPayload struct {
+	BlogPostID string `json:"blog_post_id"`
+}
+
 type BlogService interface {
-	CreateBlogPost(title, content, tags string) (*models.BlogPost, error)
+	CreateBlogPost(title, content string, tags []string, publication BlogPublication, author string) (*models.BlogPost, error)
 	GetBlogPost(id string) (*models.BlogPost, error)
-	GetAllBlogPosts() ([]models.BlogPost, error)
-	UpdateBlogPost(id, title, content, tags string) (*models.BlogPost, error)
+	GetBlogPostBySlug(slug string) (*models.BlogPost, error)
+	GetAllBlogPosts(tags []string, status string) ([]models.BlogPost, error)
+	UpdateBlogPost(id, title, content string, tags []string, author string) (*models.BlogPost, error)
+	ChangeBlogStatus(id string, publication BlogPublication) (*models.BlogPost, error)
 	DeleteBlogPost(id string) error
+	RestoreBlogPost(id string) (*models.BlogPost, error)
+	RevertBlogPost(id string, number int, author string) (*models.BlogPost, error)
 }
 
 type blogService struct {
-	repo     repository.BlogRepository
-	cache    *cache.RedisCache
-	rabbitmq *messaging.RabbitMQ
+	repo      repository.BlogRepository
+	tagRepo   repository.TagRepository
+	revisions revisionRecorder
+	cache     *cache.RedisCache
+	rabbitmq  *messaging.RabbitMQ
+	scheduler *scheduler.Scheduler
 }
 
-func NewBlogService(repo repository.BlogRepository, cache *cache.RedisCache, rabbitmq *messaging.RabbitMQ) BlogService {
-	return &blogService{
-		repo:     repo,
-		cache:    cache,
-		rabbitmq: rabbitmq,
+func NewBlogService(repo repository.BlogRepository, tagRepo repository.TagRepository, revisionRepo repository.RevisionRepository, cache *cache.RedisCache, rabbitmq *messaging.RabbitMQ, sched *scheduler.Scheduler) BlogService {
+	s := &blogService{
+		repo:      repo,
+		tagRepo:   tagRepo,
+		revisions: revisionRecorder{repo: revisionRepo},
+		cache:     cache,
+		rabbitmq:  rabbitmq,
+		scheduler: sched,
 	}
+	sched.Register(JobBlogPublish, s.handlePublish)
+	return s
 }
 
-// CreateBlogPost creates a new blog post
-func (s *blogService) CreateBlogPost(title, content, tags string) (*models.BlogPost, error) {
-	blog := &models.BlogPost{
-		Title:   title,
-		Content: content,
-		Tags:    tags,
+// CreateBlogPost creates a new blog post and records its first revision
+func (s *blogService) CreateBlogPost(title, content string, tags []string, publication BlogPublication, author string) (*models.BlogPost, error) {
+	if publication.Status == "" {
+		publication.Status = models.BlogPublished
+	}
+	blog := &models.BlogPost{}
+	if err := applyPublication(blog, publication, time.Now()); err != nil {
+		return nil, err
 	}
 
+	tagModels, err := s.tagRepo.FindOrCreate(models.NormalizeTagNames(tags))
+	if err != nil {
+		return nil, fmt.Errorf("태그 처리 실패: %w", err)
+	}
+
+	postSlug, err := s.uniqueSlug(title, "")
+	if err != nil {
+		return nil, err
+	}
+
+	blog.Title = title
+	blog.Slug = postSlug
+	blog.Content = content
+	blog.Tags = tagModels
+
 	// Save to database
 	if err := s.repo.Create(blog); err != nil {
 		return nil, fmt.Errorf("블로그 포스트 생성 실패: %w", err)
 	}
+	s.revisions.record(models.RevisionOwnerBlog, blog.ID, models.RevisionCreated, author, nil, models.NewBlogSnapshot(blog))
+	s.schedulePublish(blog)
 
 	// Publish event for async processing
 	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
@@ -54,6 +118,9 @@ func (s *blogService) CreateBlogPost(title, content, tags string) (*models.BlogP
 	}); err != nil {
 		log.Printf("경고: 이벤트 발행 실패: %v", err)
 	}
+	if blog.Status == models.BlogPublished {
+		s.publishedEvent(blog)
+	}
 
 	log.Printf("✓ 블로그 포스트 생성 완료: %s", blog.ID)
 	return blog, nil
@@ -64,7 +131,24 @@ func (s *blogService) GetBlogPost(id string) (*models.BlogPost, error) {
 	blog, err := s.repo.FindByID(id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("블로그 포스트를 찾을 수 없습니다")
+			return nil, ErrBlogPostNotFound
+		}
+		return nil, fmt.Errorf("블로그 포스트 조회 실패: %w", err)
+	}
+
+	return blog, nil
+}
+
+// GetBlogPostBySlug retrieves a blog post by its current or a former slug.
+// Callers compare the returned post's Slug to redirect former slugs.
+func (s *blogService) GetBlogPostBySlug(slug string) (*models.BlogPost, error) {
+	blog, err := s.repo.FindBySlug(slug)
+	if err == gorm.ErrRecordNotFound {
+		blog, err = s.repo.FindByFormerSlug(slug)
+	}
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrBlogPostNotFound
 		}
 		return nil, fmt.Errorf("블로그 포스트 조회 실패: %w", err)
 	}
@@ -72,36 +156,90 @@ func (s *blogService) GetBlogPost(id string) (*models.BlogPost, error) {
 	return blog, nil
 }
 
-// GetAllBlogPosts retrieves all blog posts
-func (s *blogService) GetAllBlogPosts() ([]models.BlogPost, error) {
-	blogs, err := s.repo.FindAll()
+// GetAllBlogPosts retrieves all blog posts, optionally only those carrying all given tags
+// and only those in the given status
+func (s *blogService) GetAllBlogPosts(tags []string, status string) ([]models.BlogPost, error) {
+	if status != "" && !models.IsValidBlogStatus(status) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBlogStatus, status)
+	}
+
+	blogs, err := s.repo.FindAll(models.NormalizeTagNames(tags), status)
 	if err != nil {
 		return nil, fmt.Errorf("블로그 포스트 목록 조회 실패: %w", err)
 	}
 	return blogs, nil
 }
 
-// UpdateBlogPost updates an existing blog post
-func (s *blogService) UpdateBlogPost(id, title, content, tags string) (*models.BlogPost, error) {
+// UpdateBlogPost updates an existing blog post; nil tags leave the current tags unchanged.
+// Every change is recorded as a revision, so the history doubles as the post's draft history.
+func (s *blogService) UpdateBlogPost(id, title, content string, tags []string, author string) (*models.BlogPost, error) {
+	return s.updateBlogPost(id, title, content, tags, author, models.RevisionUpdated)
+}
+
+// RevertBlogPost restores the title, content and tags stored in the given revision.
+// The revert is recorded as a new revision; later revisions are kept.
+func (s *blogService) RevertBlogPost(id string, number int, author string) (*models.BlogPost, error) {
+	if _, err := s.GetBlogPost(id); err != nil {
+		return nil, err
+	}
+
+	var snapshot models.BlogSnapshot
+	if err := s.revisions.snapshot(models.RevisionOwnerBlog, id, number, &snapshot); err != nil {
+		return nil, err
+	}
+	tags := snapshot.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return s.updateBlogPost(id, snapshot.Title, snapshot.Content, tags, author, models.RevisionReverted)
+}
+
+// updateBlogPost saves the new fields and records a revision with the given action
+func (s *blogService) updateBlogPost(id, title, content string, tags []string, author, action string) (*models.BlogPost, error) {
 	// Find existing blog post
 	blog, err := s.repo.FindByID(id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("블로그 포스트를 찾을 수 없습니다")
+			return nil, ErrBlogPostNotFound
 		}
 		return nil, fmt.Errorf("블로그 포스트 조회 실패: %w", err)
 	}
 
-	// Update fields
+	// Update fields; a new title gets a new slug and the old one keeps redirecting
+	before := models.NewBlogSnapshot(blog)
+	oldSlug := blog.Slug
+	if title != blog.Title || blog.Slug == "" {
+		if blog.Slug, err = s.uniqueSlug(title, blog.ID); err != nil {
+			return nil, err
+		}
+	}
 	blog.Title = title
 	blog.Content = content
-	blog.Tags = tags
 
 	// Save to database
 	if err := s.repo.Update(blog); err != nil {
 		return nil, fmt.Errorf("블로그 포스트 업데이트 실패: %w", err)
 	}
 
+	if blog.Slug != oldSlug {
+		if err := s.repo.RecordSlugChange(blog.ID, oldSlug, blog.Slug); err != nil {
+			return nil, fmt.Errorf("슬러그 이력 저장 실패: %w", err)
+		}
+	}
+
+	if tags != nil {
+		tagModels, err := s.tagRepo.FindOrCreate(models.NormalizeTagNames(tags))
+		if err != nil {
+			return nil, fmt.Errorf("태그 처리 실패: %w", err)
+		}
+		if err := s.repo.ReplaceTags(blog, tagModels); err != nil {
+			return nil, fmt.Errorf("태그 업데이트 실패: %w", err)
+		}
+		blog.Tags = tagModels
+	}
+
+	s.revisions.record(models.RevisionOwnerBlog, blog.ID, action, author, before, models.NewBlogSnapshot(blog))
+
 	// Publish event
 	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
 		Action: "blog_updated",
@@ -115,10 +253,167 @@ func (s *blogService) UpdateBlogPost(id, title, content, tags string) (*models.B
 	return blog, nil
 }
 
-// DeleteBlogPost deletes a blog post
+// ChangeBlogStatus moves a post between draft, scheduled, published and unlisted.
+// Scheduled posts are published by a JobBlogPublish job at their published_at.
+func (s *blogService) ChangeBlogStatus(id string, publication BlogPublication) (*models.BlogPost, error) {
+	blog, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrBlogPostNotFound
+		}
+		return nil, fmt.Errorf("블로그 포스트 조회 실패: %w", err)
+	}
+
+	previous := blog.Status
+	if err := applyPublication(blog, publication, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(blog); err != nil {
+		return nil, fmt.Errorf("블로그 포스트 상태 변경 실패: %w", err)
+	}
+	s.schedulePublish(blog)
+
+	// Publish event
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "blog_status_changed",
+		TodoID: blog.ID,
+		Data:   blog,
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+	if blog.Status == models.BlogPublished && previous != models.BlogPublished {
+		s.publishedEvent(blog)
+	}
+
+	log.Printf("✓ 블로그 포스트 상태 변경 완료: %s (%s → %s)", blog.ID, previous, blog.Status)
+	return blog, nil
+}
+
+// applyPublication validates the requested status and sets it on the post with its
+// published_at. Published and unlisted posts keep an earlier publication time unless
+// a new one is given; a draft forgets a publication time that has not arrived yet.
+func applyPublication(blog *models.BlogPost, publication BlogPublication, now time.Time) error {
+	if !models.IsValidBlogStatus(publication.Status) {
+		return fmt.Errorf("%w: %s", ErrInvalidBlogStatus, publication.Status)
+	}
+
+	publishedAt := publication.PublishedAt
+	switch publication.Status {
+	case models.BlogScheduled:
+		if publishedAt == nil || !publishedAt.After(now) {
+			return fmt.Errorf("%w: 예약 게시는 미래의 published_at 이 필요합니다", ErrInvalidPublishTime)
+		}
+	case models.BlogPublished, models.BlogUnlisted:
+		if publishedAt != nil && publishedAt.After(now) {
+			return fmt.Errorf("%w: 미래 시각에 게시하려면 scheduled 상태를 사용하세요", ErrInvalidPublishTime)
+		}
+		if publishedAt == nil {
+			publishedAt = blog.PublishedAt
+		}
+		if publishedAt == nil || publishedAt.After(now) {
+			publishedAt = &now
+		}
+	case models.BlogDraft:
+		if publishedAt == nil && blog.PublishedAt != nil && !blog.PublishedAt.After(now) {
+			publishedAt = blog.PublishedAt
+		}
+		if publishedAt != nil && publishedAt.After(now) {
+			publishedAt = nil
+		}
+	}
+
+	blog.Status = publication.Status
+	blog.PublishedAt = publishedAt
+	return nil
+}
+
+// schedulePublish keeps the post's JobBlogPublish job in line with its status
+func (s *blogService) schedulePublish(blog *models.BlogPost) {
+	key := publishKey(blog.ID)
+	if blog.Status != models.BlogScheduled || blog.PublishedAt == nil {
+		if err := s.scheduler.Cancel(key); err != nil {
+			log.Printf("경고: 게시 예약 취소 실패: %v", err)
+		}
+		return
+	}
+
+	if err := s.scheduler.ScheduleAt(key, JobBlogPublish, *blog.PublishedAt, publishPayload{BlogPostID: blog.ID}); err != nil {
+		log.Printf("경고: 게시 예약 실패: %v", err)
+	}
+}
+
+// handlePublish publishes a scheduled post whose time has come
+func (s *blogService) handlePublish(ctx context.Context, job *models.ScheduledJob) error {
+	var payload publishPayload
+	if err := scheduler.DecodePayload(job, &payload); err != nil {
+		return fmt.Errorf("게시 예약 데이터 해석 실패: %w", err)
+	}
+
+	blog, err := s.repo.FindByID(payload.BlogPostID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// 휴지통에 있는 포스트는 복원할 때 다시 예약됩니다
+			return nil
+		}
+		return fmt.Errorf("블로그 포스트 조회 실패: %w", err)
+	}
+	if blog.Status != models.BlogScheduled || blog.PublishedAt == nil || blog.PublishedAt.After(time.Now()) {
+		return nil
+	}
+
+	blog.Status = models.BlogPublished
+	if err := s.repo.Update(blog); err != nil {
+		return fmt.Errorf("블로그 포스트 게시 실패: %w", err)
+	}
+	s.publishedEvent(blog)
+
+	log.Printf("✓ 예약된 블로그 포스트 게시 완료: %s", blog.ID)
+	return nil
+}
+
+// publishedEvent announces that the post became public
+func (s *blogService) publishedEvent(blog *models.BlogPost) {
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "blog_published",
+		TodoID: blog.ID,
+		Data:   blog,
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+}
+
+func publishKey(blogPostID string) string {
+	return JobBlogPublish + ":" + blogPostID
+}
+
+// maxSlugSuffix bounds the collision suffixes tried for one title
+const maxSlugSuffix = 1000
+
+// uniqueSlug derives a slug from the title that no other post has or had, adding
+// "-2", "-3", ... on collisions. postID is the post being renamed ("" for a new post).
+func (s *blogService) uniqueSlug(title, postID string) (string, error) {
+	base := slug.Make(title)
+	for n := 1; n <= maxSlugSuffix; n++ {
+		candidate := slug.WithSuffix(base, n)
+		taken, err := s.repo.SlugTaken(candidate, postID)
+		if err != nil {
+			return "", fmt.Errorf("슬러그 확인 실패: %w", err)
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("슬러그 생성 실패: %s", base)
+}
+
+// DeleteBlogPost moves a blog post to the trash
 func (s *blogService) DeleteBlogPost(id string) error {
-	// Delete from database
+	// Soft delete; the post can be restored until the trash is purged
 	if err := s.repo.Delete(id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrBlogPostNotFound
+		}
 		return fmt.Errorf("블로그 포스트 삭제 실패: %w", err)
 	}
 
@@ -133,3 +428,32 @@ func (s *blogService) DeleteBlogPost(id string) error {
 	log.Printf("✓ 블로그 포스트 삭제 완료: %s", id)
 	return nil
 }
+
+// RestoreBlogPost takes a blog post out of the trash
+func (s *blogService) RestoreBlogPost(id string) (*models.BlogPost, error) {
+	restored, err := s.repo.Restore(id)
+	if err != nil {
+		return nil, fmt.Errorf("블로그 포스트 복원 실패: %w", err)
+	}
+	if !restored {
+		return nil, ErrBlogPostNotInTrash
+	}
+
+	blog, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("블로그 포스트 조회 실패: %w", err)
+	}
+	s.schedulePublish(blog)
+
+	// Publish event
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "blog_restored",
+		TodoID: blog.ID,
+		Data:   blog,
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ 블로그 포스트 복원 완료: %s", blog.ID)
+	return blog, nil
+}
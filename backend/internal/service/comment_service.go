@@ -0,0 +1,214 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"testbox/internal/messaging"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ErrCommentNotFound is returned when the comment does not exist on the todo
+var ErrCommentNotFound = errors.New("댓글을 찾을 수 없습니다")
+
+// ErrNotCommentAuthor is returned when someone other than the author edits or deletes a comment
+var ErrNotCommentAuthor = errors.New("작성자만 댓글을 수정하거나 삭제할 수 있습니다")
+
+type CommentService interface {
+	CreateComment(todoID, author, body string) (*models.Comment, error)
+	GetComments(todoID string) ([]models.Comment, error)
+	UpdateComment(todoID, id, author, body string) (*models.Comment, error)
+	DeleteComment(todoID, id, author string) error
+	GetTimeline(todoID string) ([]models.TimelineItem, error)
+}
+
+type commentService struct {
+	repo         repository.CommentRepository
+	activityRepo repository.ActivityRepository
+	todoRepo     repository.TodoRepository
+	rabbitmq     *messaging.RabbitMQ
+}
+
+func NewCommentService(repo repository.CommentRepository, activityRepo repository.ActivityRepository, todoRepo repository.TodoRepository, rabbitmq *messaging.RabbitMQ) CommentService {
+	return &commentService{
+		repo:         repo,
+		activityRepo: activityRepo,
+		todoRepo:     todoRepo,
+		rabbitmq:     rabbitmq,
+	}
+}
+
+// CreateComment adds a comment to a todo and publishes "comment_created" for notification consumers
+func (s *commentService) CreateComment(todoID, author, body string) (*models.Comment, error) {
+	todo, err := s.todoRepo.FindByID(todoID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+
+	comment := &models.Comment{
+		TodoID: todoID,
+		Author: author,
+		Body:   body,
+	}
+	if err := s.repo.Create(comment); err != nil {
+		return nil, fmt.Errorf("댓글 생성 실패: %w", err)
+	}
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "comment_created",
+		TodoID: todoID,
+		Data: map[string]interface{}{
+			"comment":    comment,
+			"todo_title": todo.Title,
+		},
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ 댓글 생성 완료: %s (todo: %s)", comment.ID, todoID)
+	return comment, nil
+}
+
+// GetComments retrieves the comments of a todo, oldest first
+func (s *commentService) GetComments(todoID string) ([]models.Comment, error) {
+	if err := s.ensureTodo(todoID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.FindByTodo(todoID)
+	if err != nil {
+		return nil, fmt.Errorf("댓글 조회 실패: %w", err)
+	}
+	return comments, nil
+}
+
+// UpdateComment changes the body of a comment; only its author may edit it
+func (s *commentService) UpdateComment(todoID, id, author, body string) (*models.Comment, error) {
+	comment, err := s.findComment(todoID, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Author != author {
+		return nil, ErrNotCommentAuthor
+	}
+
+	comment.Body = body
+	comment.Edited = true
+	if err := s.repo.Update(comment); err != nil {
+		return nil, fmt.Errorf("댓글 수정 실패: %w", err)
+	}
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "comment_updated",
+		TodoID: todoID,
+		Data:   comment,
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ 댓글 수정 완료: %s", comment.ID)
+	return comment, nil
+}
+
+// DeleteComment removes a comment; only its author may delete it
+func (s *commentService) DeleteComment(todoID, id, author string) error {
+	comment, err := s.findComment(todoID, id)
+	if err != nil {
+		return err
+	}
+	if comment.Author != author {
+		return ErrNotCommentAuthor
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return fmt.Errorf("댓글 삭제 실패: %w", err)
+	}
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "comment_deleted",
+		TodoID: todoID,
+		Data:   map[string]string{"comment_id": id},
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ 댓글 삭제 완료: %s", id)
+	return nil
+}
+
+// GetTimeline merges the todo's comments and recorded activity into one list, oldest first
+func (s *commentService) GetTimeline(todoID string) ([]models.TimelineItem, error) {
+	if err := s.ensureTodo(todoID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.FindByTodo(todoID)
+	if err != nil {
+		return nil, fmt.Errorf("댓글 조회 실패: %w", err)
+	}
+	activities, err := s.activityRepo.FindByTodo(todoID)
+	if err != nil {
+		return nil, fmt.Errorf("활동 기록 조회 실패: %w", err)
+	}
+
+	items := make([]models.TimelineItem, 0, len(comments)+len(activities))
+	for i := range comments {
+		items = append(items, models.TimelineItem{
+			Type:    models.TimelineComment,
+			At:      comments[i].CreatedAt,
+			Actor:   comments[i].Author,
+			Comment: &comments[i],
+		})
+	}
+	for _, activity := range activities {
+		item := models.TimelineItem{
+			Type:   models.TimelineActivity,
+			At:     activity.CreatedAt,
+			Actor:  activity.Actor,
+			Action: activity.Action,
+		}
+		if activity.Data != "" {
+			item.Data = json.RawMessage(activity.Data)
+		}
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].At.Before(items[j].At)
+	})
+	return items, nil
+}
+
+// findComment loads a comment and checks that it belongs to the todo
+func (s *commentService) findComment(todoID, id string) (*models.Comment, error) {
+	comment, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("댓글 조회 실패: %w", err)
+	}
+	if comment.TodoID != todoID {
+		return nil, ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// ensureTodo checks that the todo exists
+func (s *commentService) ensureTodo(todoID string) error {
+	if _, err := s.todoRepo.FindByID(todoID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrTodoNotFound
+		}
+		return fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"testbox/internal/cache"
+	"testbox/internal/messaging"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type ListService interface {
+	CreateList(name, description, color string) (*models.List, error)
+	GetList(id string) (*models.ListSummary, error)
+	GetAllLists() ([]models.ListSummary, error)
+	UpdateList(id, name, description, color string) (*models.List, error)
+	DeleteList(id string) error
+	GetListTodos(id string, query repository.TodoQuery) (*repository.TodoPage, error)
+}
+
+type listService struct {
+	repo     repository.ListRepository
+	todoRepo repository.TodoRepository
+	cache    *cache.RedisCache
+	rabbitmq *messaging.RabbitMQ
+}
+
+func NewListService(repo repository.ListRepository, todoRepo repository.TodoRepository, cache *cache.RedisCache, rabbitmq *messaging.RabbitMQ) ListService {
+	return &listService{
+		repo:     repo,
+		todoRepo: todoRepo,
+		cache:    cache,
+		rabbitmq: rabbitmq,
+	}
+}
+
+// CreateList creates a new list
+func (s *listService) CreateList(name, description, color string) (*models.List, error) {
+	list := &models.List{
+		Name:        name,
+		Description: description,
+		Color:       color,
+	}
+
+	if err := s.repo.Create(list); err != nil {
+		return nil, fmt.Errorf("목록 생성 실패: %w", err)
+	}
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "list_created",
+		TodoID: list.ID,
+		Data:   list,
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ 목록 생성 완료: %s", list.ID)
+	return list, nil
+}
+
+// GetList retrieves a list with its open/done counts (counts are cached per list)
+func (s *listService) GetList(id string) (*models.ListSummary, error) {
+	list, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrListNotFound
+		}
+		return nil, fmt.Errorf("목록 조회 실패: %w", err)
+	}
+
+	counts, err := s.cache.GetListCounts(id)
+	if err != nil {
+		log.Printf("경고: 캐시 오류: %v", err)
+	}
+	if counts == nil {
+		counts, err = s.repo.CountTodos(id)
+		if err != nil {
+			return nil, fmt.Errorf("목록 집계 실패: %w", err)
+		}
+		if err := s.cache.SetListCounts(id, counts); err != nil {
+			log.Printf("경고: 캐시 저장 실패: %v", err)
+		}
+	}
+
+	return &models.ListSummary{List: *list, ListCounts: *counts}, nil
+}
+
+// GetAllLists retrieves all lists with their open/done counts
+func (s *listService) GetAllLists() ([]models.ListSummary, error) {
+	lists, err := s.repo.FindAllWithCounts()
+	if err != nil {
+		return nil, fmt.Errorf("목록 조회 실패: %w", err)
+	}
+	return lists, nil
+}
+
+// UpdateList updates name, description and color of a list
+func (s *listService) UpdateList(id, name, description, color string) (*models.List, error) {
+	list, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrListNotFound
+		}
+		return nil, fmt.Errorf("목록 조회 실패: %w", err)
+	}
+
+	list.Name = name
+	list.Description = description
+	list.Color = color
+
+	if err := s.repo.Update(list); err != nil {
+		return nil, fmt.Errorf("목록 업데이트 실패: %w", err)
+	}
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "list_updated",
+		TodoID: list.ID,
+		Data:   list,
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ 목록 업데이트 완료: %s", list.ID)
+	return list, nil
+}
+
+// DeleteList deletes a list; its todos are moved to the inbox (no list)
+func (s *listService) DeleteList(id string) error {
+	todoIDs, err := s.repo.Delete(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrListNotFound
+		}
+		return fmt.Errorf("목록 삭제 실패: %w", err)
+	}
+
+	// 소속 Todo 의 list_id 가 바뀌었으므로 해당 Todo 캐시도 함께 비웁니다
+	if err := s.cache.DeleteTodos(todoIDs); err != nil {
+		log.Printf("경고: 캐시 삭제 실패: %v", err)
+	}
+	if err := s.cache.InvalidateListCounts(id); err != nil {
+		log.Printf("경고: 캐시 삭제 실패: %v", err)
+	}
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "list_deleted",
+		TodoID: id,
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ 목록 삭제 완료: %s", id)
+	return nil
+}
+
+// GetListTodos retrieves a page of todos that belong to the list
+func (s *listService) GetListTodos(id string, query repository.TodoQuery) (*repository.TodoPage, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrListNotFound
+		}
+		return nil, fmt.Errorf("목록 조회 실패: %w", err)
+	}
+
+	query.ListID = &id
+	page, err := s.todoRepo.FindPage(query)
+	if err != nil {
+		return nil, fmt.Errorf("Todo 목록 조회 실패: %w", err)
+	}
+	return page, nil
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ErrRevisionNotFound is returned when the requested revision does not exist
+var ErrRevisionNotFound = errors.New("수정 이력을 찾을 수 없습니다")
+
+// RevisionService reads the change history of todos and blog posts.
+// Revisions are recorded by TodoService and BlogService; reverting also goes through them.
+type RevisionService interface {
+	GetRevisions(ownerType, ownerID string) ([]models.RevisionView, error)
+	GetRevision(ownerType, ownerID string, number int) (*models.RevisionView, error)
+	DiffRevisions(ownerType, ownerID string, from, to int) (*models.RevisionDiff, error)
+}
+
+type revisionService struct {
+	repo     repository.RevisionRepository
+	todoRepo repository.TodoRepository
+	blogRepo repository.BlogRepository
+}
+
+func NewRevisionService(repo repository.RevisionRepository, todoRepo repository.TodoRepository, blogRepo repository.BlogRepository) RevisionService {
+	return &revisionService{
+		repo:     repo,
+		todoRepo: todoRepo,
+		blogRepo: blogRepo,
+	}
+}
+
+// GetRevisions returns the revisions of a todo or blog post, newest first
+func (s *revisionService) GetRevisions(ownerType, ownerID string) ([]models.RevisionView, error) {
+	if err := s.ensureOwner(ownerType, ownerID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.repo.FindByOwner(ownerType, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("수정 이력 조회 실패: %w", err)
+	}
+
+	views := make([]models.RevisionView, len(revisions))
+	for i := range revisions {
+		views[i] = revisions[i].View()
+	}
+	return views, nil
+}
+
+// GetRevision returns a single revision with its snapshot
+func (s *revisionService) GetRevision(ownerType, ownerID string, number int) (*models.RevisionView, error) {
+	if err := s.ensureOwner(ownerType, ownerID); err != nil {
+		return nil, err
+	}
+
+	revision, err := findRevision(s.repo, ownerType, ownerID, number)
+	if err != nil {
+		return nil, err
+	}
+	view := revision.View()
+	return &view, nil
+}
+
+// DiffRevisions lists the fields that differ between two revisions
+func (s *revisionService) DiffRevisions(ownerType, ownerID string, from, to int) (*models.RevisionDiff, error) {
+	if err := s.ensureOwner(ownerType, ownerID); err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := findRevision(s.repo, ownerType, ownerID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := findRevision(s.repo, ownerType, ownerID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := models.DiffSnapshots(fromRevision.Snapshot, toRevision.Snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("수정 이력 비교 실패: %w", err)
+	}
+	return &models.RevisionDiff{From: from, To: to, Changes: changes}, nil
+}
+
+// ensureOwner checks that the todo or blog post exists
+func (s *revisionService) ensureOwner(ownerType, ownerID string) error {
+	var err error
+	notFound := ErrTodoNotFound
+	switch ownerType {
+	case models.RevisionOwnerTodo:
+		_, err = s.todoRepo.FindByID(ownerID)
+	case models.RevisionOwnerBlog:
+		_, err = s.blogRepo.FindByID(ownerID)
+		notFound = ErrBlogPostNotFound
+	default:
+		return fmt.Errorf("알 수 없는 이력 대상: %s", ownerType)
+	}
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return notFound
+		}
+		return fmt.Errorf("이력 대상 조회 실패: %w", err)
+	}
+	return nil
+}
+
+// findRevision loads a revision and maps a missing one to ErrRevisionNotFound
+func findRevision(repo repository.RevisionRepository, ownerType, ownerID string, number int) (*models.Revision, error) {
+	revision, err := repo.FindByNumber(ownerType, ownerID, number)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("수정 이력 조회 실패: %w", err)
+	}
+	return revision, nil
+}
+
+// revisionRecorder stores a snapshot after each change. Failures are only logged so
+// that history never blocks an edit.
+type revisionRecorder struct {
+	repo repository.RevisionRepository
+}
+
+// record stores after as a new revision unless it equals the latest one.
+// Items created before revisions existed get their previous state (before) recorded first
+// so the first change can be diffed and reverted; before may be nil for new items.
+func (r revisionRecorder) record(ownerType, ownerID, action, author string, before, after interface{}) {
+	snapshot, err := json.Marshal(after)
+	if err != nil {
+		log.Printf("경고: 수정 이력 인코딩 실패: %v", err)
+		return
+	}
+
+	latest, err := r.repo.FindLatest(ownerType, ownerID)
+	if err != nil {
+		log.Printf("경고: 수정 이력 조회 실패: %v", err)
+		return
+	}
+
+	previous := ""
+	switch {
+	case latest != nil:
+		previous = latest.Snapshot
+	case before != nil:
+		initial, err := json.Marshal(before)
+		if err != nil {
+			log.Printf("경고: 수정 이력 인코딩 실패: %v", err)
+			return
+		}
+		previous = string(initial)
+		if err := r.repo.Create(&models.Revision{
+			OwnerType: ownerType,
+			OwnerID:   ownerID,
+			Action:    models.RevisionInitial,
+			Snapshot:  previous,
+		}); err != nil {
+			log.Printf("경고: 수정 이력 저장 실패: %v", err)
+			return
+		}
+	}
+
+	changes, err := models.DiffSnapshots(previous, string(snapshot))
+	if err != nil {
+		log.Printf("경고: 수정 이력 비교 실패: %v", err)
+		return
+	}
+	if previous != "" && len(changes) == 0 {
+		return
+	}
+
+	fields := make([]string, len(changes))
+	for i, change := range changes {
+		fields[i] = change.Field
+	}
+	if err := r.repo.Create(&models.Revision{
+		OwnerType:     ownerType,
+		OwnerID:       ownerID,
+		Action:        action,
+		Author:        author,
+		ChangedFields: strings.Join(fields, ","),
+		Snapshot:      string(snapshot),
+	}); err != nil {
+		log.Printf("경고: 수정 이력 저장 실패: %v", err)
+	}
+}
+
+// snapshot decodes the revision into a TodoSnapshot or BlogSnapshot
+func (r revisionRecorder) snapshot(ownerType, ownerID string, number int, v interface{}) error {
+	revision, err := findRevision(r.repo, ownerType, ownerID, number)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(revision.Snapshot), v); err != nil {
+		return fmt.Errorf("수정 이력 해석 실패: %w", err)
+	}
+	return nil
+}
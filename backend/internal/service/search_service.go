@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+	"testbox/internal/search"
+)
+
+// ErrEmptySearch is returned when the search text is blank
+var ErrEmptySearch = errors.New("검색어가 필요합니다")
+
+// ErrInvalidSearchType is returned for a type other than todo or blog
+var ErrInvalidSearchType = errors.New("검색 대상은 todo 또는 blog 여야 합니다")
+
+// SearchService runs full-text searches across todos and blog posts on the configured
+// search.Searcher backend
+type SearchService interface {
+	Search(text string, types []string, limit, offset int, publishedOnly bool) (*repository.SearchPage, error)
+}
+
+type searchService struct {
+	searcher search.Searcher
+}
+
+func NewSearchService(searcher search.Searcher) SearchService {
+	return &searchService{searcher: searcher}
+}
+
+// Search returns the matches of text in titles, content and tag names, best match first.
+// publishedOnly leaves out blog posts that are not published.
+func (s *searchService) Search(text string, types []string, limit, offset int, publishedOnly bool) (*repository.SearchPage, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrEmptySearch
+	}
+	for _, t := range types {
+		if t != models.SearchTypeTodo && t != models.SearchTypeBlog {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSearchType, t)
+		}
+	}
+
+	page, err := s.searcher.Search(repository.SearchQuery{
+		Text:          text,
+		Types:         types,
+		Limit:         limit,
+		Offset:        offset,
+		PublishedOnly: publishedOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("검색 실패: %w", err)
+	}
+	return page, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"testbox/internal/cache"
+	"testbox/internal/messaging"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTagNotFound 는 지정한 태그가 존재하지 않을 때 반환됩니다
+	ErrTagNotFound = errors.New("태그를 찾을 수 없습니다")
+	// ErrTagExists 는 이름을 바꾸려는 태그 이름이 이미 사용 중일 때 반환됩니다
+	ErrTagExists = errors.New("같은 이름의 태그가 이미 있습니다")
+	// ErrTagMergeSelf 는 태그를 자기 자신과 병합하려 할 때 반환됩니다
+	ErrTagMergeSelf = errors.New("같은 태그끼리는 병합할 수 없습니다")
+)
+
+type TagService interface {
+	GetAllTags() ([]models.TagUsage, error)
+	RenameTag(id, name string) (*models.Tag, error)
+	MergeTags(sourceID, targetID string) (*models.Tag, error)
+}
+
+type tagService struct {
+	repo     repository.TagRepository
+	cache    *cache.RedisCache
+	rabbitmq *messaging.RabbitMQ
+}
+
+func NewTagService(repo repository.TagRepository, cache *cache.RedisCache, rabbitmq *messaging.RabbitMQ) TagService {
+	return &tagService{
+		repo:     repo,
+		cache:    cache,
+		rabbitmq: rabbitmq,
+	}
+}
+
+// GetAllTags retrieves every tag with its todo and blog post usage counts
+func (s *tagService) GetAllTags() ([]models.TagUsage, error) {
+	tags, err := s.repo.FindAllWithUsage()
+	if err != nil {
+		return nil, fmt.Errorf("태그 목록 조회 실패: %w", err)
+	}
+	return tags, nil
+}
+
+// RenameTag renames a tag; use MergeTags to fold it into an existing tag instead
+func (s *tagService) RenameTag(id, name string) (*models.Tag, error) {
+	tag, err := s.findTag(id)
+	if err != nil {
+		return nil, err
+	}
+
+	name = models.NormalizeTagName(name)
+	if name == tag.Name {
+		return tag, nil
+	}
+	if _, err := s.repo.FindByName(name); err == nil {
+		return nil, ErrTagExists
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("태그 조회 실패: %w", err)
+	}
+
+	oldName := tag.Name
+	tag.Name = name
+	if err := s.repo.Update(tag); err != nil {
+		return nil, fmt.Errorf("태그 이름 변경 실패: %w", err)
+	}
+
+	// 캐시된 Todo 에는 태그 이름이 포함되어 있으므로 무효화합니다
+	s.invalidateTaggedTodos(tag.ID)
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "tag_renamed",
+		TodoID: tag.ID,
+		Data: map[string]string{
+			"from": oldName,
+			"to":   tag.Name,
+		},
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ 태그 이름 변경 완료: %s → %s", oldName, tag.Name)
+	return tag, nil
+}
+
+// MergeTags moves every usage of the source tag to the target tag and deletes the source
+func (s *tagService) MergeTags(sourceID, targetID string) (*models.Tag, error) {
+	if sourceID == targetID {
+		return nil, ErrTagMergeSelf
+	}
+
+	source, err := s.findTag(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.findTag(targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 병합 전에 영향받는 Todo 를 구해 두어야 캐시를 비울 수 있습니다
+	todoIDs, err := s.repo.FindTodoIDs(source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("태그 사용처 조회 실패: %w", err)
+	}
+
+	if err := s.repo.Merge(source.ID, target.ID); err != nil {
+		return nil, fmt.Errorf("태그 병합 실패: %w", err)
+	}
+
+	if err := s.cache.DeleteTodos(todoIDs); err != nil {
+		log.Printf("경고: 캐시 삭제 실패: %v", err)
+	}
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "tag_merged",
+		TodoID: target.ID,
+		Data: map[string]string{
+			"source_id": source.ID,
+			"source":    source.Name,
+			"target":    target.Name,
+		},
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ 태그 병합 완료: %s → %s", source.Name, target.Name)
+	return target, nil
+}
+
+func (s *tagService) findTag(id string) (*models.Tag, error) {
+	tag, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("태그 조회 실패: %w", err)
+	}
+	return tag, nil
+}
+
+func (s *tagService) invalidateTaggedTodos(tagID string) {
+	todoIDs, err := s.repo.FindTodoIDs(tagID)
+	if err != nil {
+		log.Printf("경고: 태그 사용처 조회 실패: %v", err)
+		return
+	}
+	if err := s.cache.DeleteTodos(todoIDs); err != nil {
+		log.Printf("경고: 캐시 삭제 실패: %v", err)
+	}
+}
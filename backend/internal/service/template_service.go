@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrTemplateNotFound is returned when the requested template does not exist
+var ErrTemplateNotFound = errors.New("템플릿을 찾을 수 없습니다")
+
+// ErrInvalidTemplate is returned when a template has no name or an item has no title
+var ErrInvalidTemplate = errors.New("템플릿 이름과 모든 항목의 제목이 필요합니다")
+
+// ErrMissingTemplateVariable is returned when instantiating without a value for a placeholder
+var ErrMissingTemplateVariable = errors.New("템플릿 변수 값이 없습니다")
+
+// templateDateVariable is filled with the start date (YYYY-MM-DD) unless given explicitly
+const templateDateVariable = "date"
+
+// TemplateInput holds the editable fields of a template
+type TemplateInput struct {
+	Name        string
+	Description string
+	Todo        models.TemplateItem
+}
+
+// InstantiateInput controls how a template is turned into todos
+type InstantiateInput struct {
+	Start     time.Time         // due offsets are counted from this day
+	ListID    *string           // list of the created todos (nil for none)
+	Variables map[string]string // values for the {{name}} placeholders
+	Actor     string
+}
+
+// TemplateService manages todo templates and creates todo trees from them.
+// Instantiating goes through TodoService so caches, events and activity stay consistent.
+type TemplateService interface {
+	CreateTemplate(input TemplateInput) (*models.Template, error)
+	GetTemplate(id string) (*models.Template, error)
+	GetAllTemplates() ([]models.Template, error)
+	UpdateTemplate(id string, input TemplateInput) (*models.Template, error)
+	DeleteTemplate(id string) error
+	Instantiate(id string, input InstantiateInput) (*models.TodoNode, error)
+}
+
+type templateService struct {
+	repo        repository.TemplateRepository
+	todoService TodoService
+}
+
+func NewTemplateService(repo repository.TemplateRepository, todoService TodoService) TemplateService {
+	return &templateService{
+		repo:        repo,
+		todoService: todoService,
+	}
+}
+
+// CreateTemplate stores a new template
+func (s *templateService) CreateTemplate(input TemplateInput) (*models.Template, error) {
+	if err := validateTemplate(input); err != nil {
+		return nil, err
+	}
+
+	template := &models.Template{
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+		Todo:        input.Todo,
+	}
+	if err := s.repo.Create(template); err != nil {
+		return nil, fmt.Errorf("템플릿 생성 실패: %w", err)
+	}
+
+	log.Printf("✓ 템플릿 생성 완료: %s", template.ID)
+	return template, nil
+}
+
+// GetTemplate retrieves a template with its items
+func (s *templateService) GetTemplate(id string) (*models.Template, error) {
+	template, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, fmt.Errorf("템플릿 조회 실패: %w", err)
+	}
+	return template, nil
+}
+
+// GetAllTemplates retrieves all templates ordered by name
+func (s *templateService) GetAllTemplates() ([]models.Template, error) {
+	templates, err := s.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("템플릿 조회 실패: %w", err)
+	}
+	return templates, nil
+}
+
+// UpdateTemplate replaces the name, description and items of a template
+func (s *templateService) UpdateTemplate(id string, input TemplateInput) (*models.Template, error) {
+	if err := validateTemplate(input); err != nil {
+		return nil, err
+	}
+
+	template, err := s.GetTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = strings.TrimSpace(input.Name)
+	template.Description = input.Description
+	template.Todo = input.Todo
+	if err := s.repo.Update(template); err != nil {
+		return nil, fmt.Errorf("템플릿 수정 실패: %w", err)
+	}
+
+	log.Printf("✓ 템플릿 수정 완료: %s", id)
+	return template, nil
+}
+
+// DeleteTemplate removes a template; todos created from it are kept
+func (s *templateService) DeleteTemplate(id string) error {
+	if err := s.repo.Delete(id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrTemplateNotFound
+		}
+		return fmt.Errorf("템플릿 삭제 실패: %w", err)
+	}
+
+	log.Printf("✓ 템플릿 삭제 완료: %s", id)
+	return nil
+}
+
+// Instantiate creates the template's todo and all of its subtasks, substituting the
+// variables in titles and content and setting due dates relative to the start date.
+// If a todo cannot be created, the todos created so far are moved to the trash.
+func (s *templateService) Instantiate(id string, input InstantiateInput) (*models.TodoNode, error) {
+	template, err := s.GetTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	start := input.Start
+	variables := map[string]string{templateDateVariable: start.Format("2006-01-02")}
+	for name, value := range input.Variables {
+		variables[name] = value
+	}
+
+	var missing []string
+	for _, name := range template.Variables {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("%w: %s", ErrMissingTemplateVariable, strings.Join(missing, ", "))
+	}
+
+	root, err := s.todoService.CreateTodo(templateTodoInput(template.Todo, start, variables, input.ListID, input.Actor))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.createSubtasks(root.ID, template.Todo.Subtasks, start, variables, input.Actor); err != nil {
+		if cleanupErr := s.todoService.DeleteTodo(root.ID); cleanupErr != nil {
+			log.Printf("경고: 템플릿 정리 실패: %v", cleanupErr)
+		}
+		return nil, err
+	}
+
+	log.Printf("✓ 템플릿 적용 완료: %s → %s", template.ID, root.ID)
+	return s.todoService.GetTodoTree(root.ID)
+}
+
+// createSubtasks creates the items under parentID, depth first
+func (s *templateService) createSubtasks(parentID string, items []models.TemplateItem, start time.Time, variables map[string]string, actor string) error {
+	for _, item := range items {
+		// 하위 Todo는 상위 Todo의 목록을 따르므로 ListID 는 지정하지 않습니다
+		todo, err := s.todoService.CreateSubtask(parentID, templateTodoInput(item, start, variables, nil, actor))
+		if err != nil {
+			return err
+		}
+		if err := s.createSubtasks(todo.ID, item.Subtasks, start, variables, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateTodoInput builds the TodoInput of a single template item
+func templateTodoInput(item models.TemplateItem, start time.Time, variables map[string]string, listID *string, actor string) TodoInput {
+	input := TodoInput{
+		Title:    substituteVariables(item.Title, variables),
+		Content:  substituteVariables(item.Content, variables),
+		Priority: item.Priority,
+		ListID:   listID,
+		Tags:     item.Tags,
+		Actor:    actor,
+	}
+	if item.DueOffsetDays != nil {
+		due := start.AddDate(0, 0, *item.DueOffsetDays)
+		input.DueDate = &due
+	}
+	return input
+}
+
+// substituteVariables replaces the {{name}} placeholders of text with their values
+func substituteVariables(text string, variables map[string]string) string {
+	return models.TemplateVariable.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := models.TemplateVariable.FindStringSubmatch(placeholder)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+func validateTemplate(input TemplateInput) error {
+	if strings.TrimSpace(input.Name) == "" {
+		return ErrInvalidTemplate
+	}
+	valid := true
+	input.Todo.Walk(func(item *models.TemplateItem) {
+		if strings.TrimSpace(item.Title) == "" {
+			valid = false
+		}
+	})
+	if !valid {
+		return ErrInvalidTemplate
+	}
+	return nil
+}
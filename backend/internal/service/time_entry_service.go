@@ -0,0 +1,209 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"testbox/internal/messaging"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrTimerRunning is returned when the user already has a running timer
+var ErrTimerRunning = errors.New("이미 실행 중인 타이머가 있습니다")
+
+// ErrNoRunningTimer is returned when there is no running timer to stop
+var ErrNoRunningTimer = errors.New("실행 중인 타이머가 없습니다")
+
+// ErrTimeEntryNotFound is returned when the time entry does not exist
+var ErrTimeEntryNotFound = errors.New("시간 기록을 찾을 수 없습니다")
+
+// ErrInvalidTimeRange is returned when a manual entry ends before it starts
+var ErrInvalidTimeRange = errors.New("종료 시각은 시작 시각 이후여야 합니다")
+
+type TimeEntryService interface {
+	StartTimer(todoID, userID, note string) (*models.TimeEntry, error)
+	StopTimer(todoID, userID string) (*models.TimeEntry, error)
+	GetRunningTimer(userID string) (*models.TimeEntry, error)
+	AddManualEntry(todoID, userID string, startedAt, endedAt time.Time, note string) (*models.TimeEntry, error)
+	GetTodoEntries(todoID string) ([]models.TimeEntry, error)
+	DeleteEntry(id string) error
+	GetReport(query repository.TimeReportQuery) ([]models.TimeReportRow, error)
+}
+
+type timeEntryService struct {
+	repo     repository.TimeEntryRepository
+	todoRepo repository.TodoRepository
+	rabbitmq *messaging.RabbitMQ
+}
+
+func NewTimeEntryService(repo repository.TimeEntryRepository, todoRepo repository.TodoRepository, rabbitmq *messaging.RabbitMQ) TimeEntryService {
+	return &timeEntryService{
+		repo:     repo,
+		todoRepo: todoRepo,
+		rabbitmq: rabbitmq,
+	}
+}
+
+// StartTimer starts a timer on the todo; a user can only have one running timer at a time
+func (s *timeEntryService) StartTimer(todoID, userID, note string) (*models.TimeEntry, error) {
+	if err := s.ensureTodo(todoID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindRunning(userID); err == nil {
+		return nil, ErrTimerRunning
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("타이머 조회 실패: %w", err)
+	}
+
+	entry := &models.TimeEntry{
+		TodoID:    todoID,
+		UserID:    userID,
+		StartedAt: time.Now(),
+		Note:      note,
+	}
+	// 동시에 시작한 경우 부분 유니크 인덱스가 두 번째 타이머를 거부합니다
+	if err := s.repo.Create(entry); err != nil {
+		if _, findErr := s.repo.FindRunning(userID); findErr == nil {
+			return nil, ErrTimerRunning
+		}
+		return nil, fmt.Errorf("타이머 시작 실패: %w", err)
+	}
+
+	s.publish("timer_started", entry)
+
+	log.Printf("✓ 타이머 시작: %s (todo: %s, user: %s)", entry.ID, todoID, userID)
+	return entry, nil
+}
+
+// StopTimer stops the user's running timer on the todo
+func (s *timeEntryService) StopTimer(todoID, userID string) (*models.TimeEntry, error) {
+	entry, err := s.repo.FindRunning(userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNoRunningTimer
+		}
+		return nil, fmt.Errorf("타이머 조회 실패: %w", err)
+	}
+	if entry.TodoID != todoID {
+		return nil, ErrNoRunningTimer
+	}
+
+	entry.Finish(time.Now())
+	if err := s.repo.Update(entry); err != nil {
+		return nil, fmt.Errorf("타이머 정지 실패: %w", err)
+	}
+
+	s.publish("timer_stopped", entry)
+
+	log.Printf("✓ 타이머 정지: %s (%d초)", entry.ID, entry.Duration)
+	return entry, nil
+}
+
+// GetRunningTimer returns the user's running timer
+func (s *timeEntryService) GetRunningTimer(userID string) (*models.TimeEntry, error) {
+	entry, err := s.repo.FindRunning(userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNoRunningTimer
+		}
+		return nil, fmt.Errorf("타이머 조회 실패: %w", err)
+	}
+	return entry, nil
+}
+
+// AddManualEntry records time that was not tracked with a timer
+func (s *timeEntryService) AddManualEntry(todoID, userID string, startedAt, endedAt time.Time, note string) (*models.TimeEntry, error) {
+	if !endedAt.After(startedAt) {
+		return nil, ErrInvalidTimeRange
+	}
+	if err := s.ensureTodo(todoID); err != nil {
+		return nil, err
+	}
+
+	entry := &models.TimeEntry{
+		TodoID:    todoID,
+		UserID:    userID,
+		StartedAt: startedAt,
+		Note:      note,
+		Manual:    true,
+	}
+	entry.Finish(endedAt)
+
+	if err := s.repo.Create(entry); err != nil {
+		return nil, fmt.Errorf("시간 기록 생성 실패: %w", err)
+	}
+
+	s.publish("time_entry_created", entry)
+
+	log.Printf("✓ 시간 기록 생성 완료: %s (%d초)", entry.ID, entry.Duration)
+	return entry, nil
+}
+
+// GetTodoEntries retrieves all time entries of a todo, newest first
+func (s *timeEntryService) GetTodoEntries(todoID string) ([]models.TimeEntry, error) {
+	if err := s.ensureTodo(todoID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.FindByTodo(todoID)
+	if err != nil {
+		return nil, fmt.Errorf("시간 기록 조회 실패: %w", err)
+	}
+	return entries, nil
+}
+
+// DeleteEntry deletes a time entry (including a running timer)
+func (s *timeEntryService) DeleteEntry(id string) error {
+	entry, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrTimeEntryNotFound
+		}
+		return fmt.Errorf("시간 기록 조회 실패: %w", err)
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return fmt.Errorf("시간 기록 삭제 실패: %w", err)
+	}
+
+	s.publish("time_entry_deleted", entry)
+
+	log.Printf("✓ 시간 기록 삭제 완료: %s", id)
+	return nil
+}
+
+// GetReport sums tracked time per todo, list or day
+func (s *timeEntryService) GetReport(query repository.TimeReportQuery) ([]models.TimeReportRow, error) {
+	rows, err := s.repo.Report(query)
+	if err != nil {
+		return nil, fmt.Errorf("시간 보고서 조회 실패: %w", err)
+	}
+	return rows, nil
+}
+
+// ensureTodo checks that the todo exists
+func (s *timeEntryService) ensureTodo(todoID string) error {
+	if _, err := s.todoRepo.FindByID(todoID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrTodoNotFound
+		}
+		return fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+	return nil
+}
+
+// publish sends a time tracking event keyed by the entry's todo
+func (s *timeEntryService) publish(action string, entry *models.TimeEntry) {
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: action,
+		TodoID: entry.TodoID,
+		Data:   entry,
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+}
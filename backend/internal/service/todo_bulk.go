@@ -0,0 +1,375 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"testbox/internal/messaging"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+	"testbox/internal/workflow"
+)
+
+// 일괄 작업 종류
+const (
+	BulkComplete   = "complete"
+	BulkUncomplete = "uncomplete"
+	BulkDelete     = "delete"
+	BulkMove       = "move"
+	BulkAddTag     = "add_tag"
+)
+
+// 일괄 작업 항목별 결과
+const (
+	BulkItemOK        = "ok"        // 변경됨
+	BulkItemUnchanged = "unchanged" // 이미 요청한 상태라 변경하지 않음
+	BulkItemFailed    = "failed"    // 검증 실패로 제외됨 (Error 에 사유)
+)
+
+// ErrInvalidBulkAction 은 알 수 없는 일괄 작업을 요청했을 때 반환됩니다
+var ErrInvalidBulkAction = errors.New("알 수 없는 일괄 작업입니다")
+
+// BulkInput 은 여러 Todo에 같은 작업을 적용하는 요청입니다
+type BulkInput struct {
+	Action string
+	IDs    []string
+	ListID *string // move 대상 목록 (nil 이면 목록 없음)
+	Tag    string  // add_tag 로 붙일 태그 이름
+	Actor  string  // 변경한 사용자 (수정 이력에 기록)
+}
+
+// BulkItemResult 는 일괄 작업에서 Todo 하나의 처리 결과입니다
+type BulkItemResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	RootID string `json:"root_id,omitempty"` // delete: 함께 요청한 상위 Todo와 같이 삭제된 경우 그 상위 Todo
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkResult 는 일괄 작업의 항목별 결과입니다
+type BulkResult struct {
+	Action    string           `json:"action"`
+	Succeeded int              `json:"succeeded"`
+	Unchanged int              `json:"unchanged"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// Applied 는 직접 변경된 Todo ID 목록을 반환합니다 (상위 Todo와 함께 삭제된 항목 제외)
+func (r *BulkResult) Applied() []string {
+	var ids []string
+	for _, item := range r.Results {
+		if item.Status == BulkItemOK && item.RootID == "" {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+// bulkPlan 은 검증을 통과한 변경 사항과 후속 처리에 필요한 Todo 들입니다
+type bulkPlan struct {
+	change   repository.TodoBulkChange
+	todos    map[string]*models.Todo // 요청한 Todo (ID 기준)
+	subtrees map[string][]string     // delete / move 대상 루트별 서브트리 ID
+	before   map[string]models.TodoSnapshot
+	tag      *models.Tag
+}
+
+// BulkUpdate 는 여러 Todo에 같은 작업을 하나의 트랜잭션으로 적용합니다.
+// 검증에 실패한 항목은 결과에 사유와 함께 남고 나머지만 적용됩니다. 저장에 실패하면 아무것도 바뀌지 않습니다.
+// 캐시는 한 번에 무효화하고, 이벤트는 단건 API와 같은 형식으로 항목마다 발행합니다.
+func (s *todoService) BulkUpdate(input BulkInput) (*BulkResult, error) {
+	// 1. 작업 및 대상 목록/태그 확인
+	switch input.Action {
+	case BulkComplete, BulkUncomplete, BulkDelete:
+	case BulkMove:
+		if err := s.ensureList(input.ListID); err != nil {
+			return nil, err
+		}
+	case BulkAddTag:
+		if len(models.NormalizeTagNames([]string{input.Tag})) == 0 {
+			return nil, fmt.Errorf("%w: 태그 이름이 필요합니다", ErrInvalidBulkAction)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBulkAction, input.Action)
+	}
+
+	// 2. 대상 Todo 조회 (중복 ID 는 한 번만 처리)
+	ids := make([]string, 0, len(input.IDs))
+	seen := make(map[string]bool, len(input.IDs))
+	for _, id := range input.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	todos, err := s.repo.FindByIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+	plan := &bulkPlan{
+		todos:    make(map[string]*models.Todo, len(todos)),
+		subtrees: map[string][]string{},
+		before:   map[string]models.TodoSnapshot{},
+	}
+	for i := range todos {
+		plan.todos[todos[i].ID] = &todos[i]
+	}
+
+	// 3. 항목별 검증 및 변경 계획 작성
+	result := &BulkResult{Action: input.Action, Results: make([]BulkItemResult, len(ids))}
+	for i, id := range ids {
+		item := BulkItemResult{ID: id, Status: BulkItemOK}
+		if todo := plan.todos[id]; todo == nil {
+			item.Status, item.Error = BulkItemFailed, ErrTodoNotFound.Error()
+		} else if status, err := s.planBulkItem(plan, input, todo); err != nil {
+			item.Status, item.Error = BulkItemFailed, err.Error()
+		} else {
+			item.Status = status
+		}
+		result.Results[i] = item
+	}
+	if input.Action == BulkDelete {
+		collapseNestedDeletes(plan, result)
+	}
+
+	// 4. 하나의 트랜잭션으로 저장
+	if err := s.repo.ApplyBulk(plan.change); err != nil {
+		return nil, fmt.Errorf("Todo 일괄 처리 실패: %w", err)
+	}
+
+	for _, item := range result.Results {
+		switch item.Status {
+		case BulkItemOK:
+			result.Succeeded++
+		case BulkItemUnchanged:
+			result.Unchanged++
+		default:
+			result.Failed++
+		}
+	}
+
+	// 5. 캐시 일괄 무효화, 이벤트 발행 등 후속 처리
+	if result.Succeeded > 0 {
+		s.afterBulk(plan, input, result.Applied())
+	}
+
+	log.Printf("✓ Todo 일괄 처리 완료: %s (성공 %d, 변경 없음 %d, 실패 %d)", input.Action, result.Succeeded, result.Unchanged, result.Failed)
+	return result, nil
+}
+
+// planBulkItem 은 Todo 하나를 검증하고 변경 계획에 추가합니다
+func (s *todoService) planBulkItem(plan *bulkPlan, input BulkInput, todo *models.Todo) (string, error) {
+	switch input.Action {
+	case BulkComplete, BulkUncomplete:
+		status := workflow.Done
+		if input.Action == BulkUncomplete {
+			status = s.workflow.Initial
+		}
+		if todo.Completed == (input.Action == BulkComplete) {
+			return BulkItemUnchanged, nil
+		}
+		if err := s.checkTransition(todo, status); err != nil {
+			return "", err
+		}
+		plan.before[todo.ID] = models.NewTodoSnapshot(todo)
+		setStatus(todo, status)
+		plan.change.Update = append(plan.change.Update, todo)
+
+	case BulkDelete:
+		subtree, err := s.repo.FindSubtree(todo.ID)
+		if err != nil {
+			return "", fmt.Errorf("Todo 조회 실패: %w", err)
+		}
+		ids := make([]string, len(subtree))
+		for i := range subtree {
+			ids[i] = subtree[i].ID
+		}
+		plan.subtrees[todo.ID] = ids
+		plan.change.DeleteIDs = append(plan.change.DeleteIDs, ids...)
+
+	case BulkMove:
+		if todo.ParentID != nil {
+			return "", ErrSubtaskMove
+		}
+		if sameList(todo.ListID, input.ListID) {
+			return BulkItemUnchanged, nil
+		}
+		subtree, err := s.repo.FindSubtree(todo.ID)
+		if err != nil {
+			return "", fmt.Errorf("Todo 트리 조회 실패: %w", err)
+		}
+		ids := make([]string, len(subtree))
+		for i := range subtree {
+			ids[i] = subtree[i].ID
+		}
+		plan.subtrees[todo.ID] = ids
+		plan.change.MoveIDs = append(plan.change.MoveIDs, ids...)
+		plan.change.ListID = input.ListID
+
+	case BulkAddTag:
+		if plan.tag == nil {
+			tags, err := s.tagRepo.FindOrCreate(models.NormalizeTagNames([]string{input.Tag}))
+			if err != nil {
+				return "", fmt.Errorf("태그 처리 실패: %w", err)
+			}
+			plan.tag = &tags[0]
+			plan.change.TagID = plan.tag.ID
+		}
+		for _, tag := range todo.Tags {
+			if tag.ID == plan.tag.ID {
+				return BulkItemUnchanged, nil
+			}
+		}
+		plan.before[todo.ID] = models.NewTodoSnapshot(todo)
+		todo.Tags = append(todo.Tags, *plan.tag)
+		plan.change.TagTodoIDs = append(plan.change.TagTodoIDs, todo.ID)
+	}
+	return BulkItemOK, nil
+}
+
+// afterBulk 는 일괄 작업으로 바뀐 Todo 들의 캐시, 이벤트, 알림, 롤업, 목록 집계를 정리합니다
+func (s *todoService) afterBulk(plan *bulkPlan, input BulkInput, applied []string) {
+	// 1. 캐시 일괄 무효화 (서브트리 포함)
+	affected := applied
+	for _, subtree := range plan.subtrees {
+		affected = append(affected, subtree...)
+	}
+	if err := s.cache.DeleteTodos(affected); err != nil {
+		log.Printf("경고: 캐시 삭제 실패: %v", err)
+	}
+
+	lists := map[string]*string{}
+	parents := map[string]bool{}
+	deleted := map[string]bool{}
+	for _, id := range plan.change.DeleteIDs {
+		deleted[id] = true
+	}
+
+	// 2. 항목별 이벤트 발행 및 후속 처리
+	for _, id := range applied {
+		todo := plan.todos[id]
+		if todo.ListID != nil {
+			lists[*todo.ListID] = todo.ListID
+		}
+
+		switch input.Action {
+		case BulkComplete, BulkUncomplete:
+			s.revisions.record(models.RevisionOwnerTodo, todo.ID, models.RevisionUpdated, input.Actor, plan.before[id], models.NewTodoSnapshot(todo))
+			fromStatus := plan.before[id].Status
+			s.publishStatusChange(todo, fromStatus)
+			if todo.Completed {
+				s.spawnNextOccurrence(todo)
+			}
+			s.scheduleReminder(todo)
+			if todo.ParentID != nil {
+				parents[*todo.ParentID] = true
+			}
+
+		case BulkDelete:
+			for _, todoID := range plan.subtrees[id] {
+				event := messaging.TodoEvent{
+					Action: "deleted",
+					TodoID: todoID,
+				}
+				if todoID != id {
+					event.Data = map[string]string{"root_id": id}
+				}
+				if err := s.rabbitmq.PublishEvent(event); err != nil {
+					log.Printf("경고: 이벤트 발행 실패: %v", err)
+				}
+				if err := s.scheduler.Cancel(reminderKey(todoID)); err != nil {
+					log.Printf("경고: 알림 예약 취소 실패: %v", err)
+				}
+			}
+			if todo.ParentID != nil {
+				parents[*todo.ParentID] = true
+			}
+
+		case BulkMove:
+			if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+				Action: "moved",
+				TodoID: todo.ID,
+				Data: map[string]interface{}{
+					"from_list_id": todo.ListID,
+					"to_list_id":   input.ListID,
+					"todo_ids":     plan.subtrees[id],
+				},
+			}); err != nil {
+				log.Printf("경고: 이벤트 발행 실패: %v", err)
+			}
+			s.recordActivity(todo.ID, "moved", map[string]interface{}{
+				"from_list_id": todo.ListID,
+				"to_list_id":   input.ListID,
+			})
+
+		case BulkAddTag:
+			s.revisions.record(models.RevisionOwnerTodo, todo.ID, models.RevisionUpdated, input.Actor, plan.before[id], models.NewTodoSnapshot(todo))
+			if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+				Action: "updated",
+				TodoID: todo.ID,
+				Data:   todo,
+			}); err != nil {
+				log.Printf("경고: 이벤트 발행 실패: %v", err)
+			}
+		}
+	}
+
+	// 3. 상위 Todo 완료 상태 재계산 (함께 삭제된 상위는 제외)
+	for parentID := range parents {
+		if !deleted[parentID] {
+			s.rollupCompletion(parentID)
+		}
+	}
+
+	// 4. 목록 집계 캐시 무효화 (완료 여부 변경, 삭제, 이동한 경우)
+	if input.Action == BulkMove && input.ListID != nil {
+		lists[*input.ListID] = input.ListID
+	}
+	if input.Action != BulkAddTag {
+		listIDs := make([]*string, 0, len(lists))
+		for _, listID := range lists {
+			listIDs = append(listIDs, listID)
+		}
+		s.invalidateListCounts(listIDs...)
+	}
+}
+
+// collapseNestedDeletes 는 함께 요청한 상위 Todo의 서브트리에 포함된 항목을 그 상위 Todo와 함께 삭제된 것으로 표시합니다.
+// 이벤트가 중복 발행되지 않고, 되돌리기 시 상위 Todo만 복원하면 됩니다.
+func collapseNestedDeletes(plan *bulkPlan, result *BulkResult) {
+	covered := map[string]string{}
+	for rootID, ids := range plan.subtrees {
+		for _, id := range ids {
+			if id != rootID {
+				covered[id] = rootID
+			}
+		}
+	}
+
+	for i := range result.Results {
+		item := &result.Results[i]
+		rootID, ok := covered[item.ID]
+		if !ok || item.Status != BulkItemOK {
+			continue
+		}
+		for {
+			next, ok := covered[rootID]
+			if !ok {
+				break
+			}
+			rootID = next
+		}
+		item.RootID = rootID
+		delete(plan.subtrees, item.ID)
+	}
+}
+
+// sameList 는 두 목록 ID 가 같은 목록(또는 둘 다 목록 없음)을 가리키는지 확인합니다
+func sameList(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
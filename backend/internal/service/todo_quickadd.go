@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testbox/internal/models"
+	"testbox/internal/quickadd"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrQuickAddNoTitle 는 빠른 추가 입력에서 제목을 찾지 못했을 때 반환됩니다
+var ErrQuickAddNoTitle = errors.New("제목이 비어 있습니다")
+
+// QuickAddPreview 는 빠른 추가 입력을 해석한 결과입니다 (저장 전 미리보기용)
+type QuickAddPreview struct {
+	quickadd.Result
+	ListID   *string  `json:"list_id"`  // List 이름으로 찾은 목록 (없으면 null)
+	Warnings []string `json:"warnings"` // 저장 시 실패할 이유 (제목 없음, 목록 없음)
+}
+
+// PreviewQuickAdd 는 "Pay rent every month on the 1st #finance !high" 같은 한 줄 입력을 해석합니다.
+// 목록 이름은 ID로 바꾸고, 저장할 수 없는 입력이면 경고를 담아 반환합니다. 아무것도 저장하지 않습니다.
+func (s *todoService) PreviewQuickAdd(text string, now time.Time) (*QuickAddPreview, error) {
+	preview := &QuickAddPreview{Result: quickadd.Parse(text, now), Warnings: []string{}}
+	if preview.Title == "" {
+		preview.Warnings = append(preview.Warnings, ErrQuickAddNoTitle.Error())
+	}
+
+	if preview.List != "" {
+		list, err := s.listRepo.FindByName(preview.List)
+		switch {
+		case err == nil:
+			preview.ListID = &list.ID
+		case err == gorm.ErrRecordNotFound:
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("%s: %s", ErrListNotFound.Error(), preview.List))
+		default:
+			return nil, fmt.Errorf("목록 조회 실패: %w", err)
+		}
+	}
+	return preview, nil
+}
+
+// QuickAdd 는 한 줄 입력을 해석해 바로 Todo를 생성합니다 (CreateTodo 와 같은 캐시/이벤트 처리)
+func (s *todoService) QuickAdd(text string, now time.Time, actor string) (*models.Todo, error) {
+	preview, err := s.PreviewQuickAdd(text, now)
+	if err != nil {
+		return nil, err
+	}
+	if preview.Title == "" {
+		return nil, ErrQuickAddNoTitle
+	}
+	if preview.List != "" && preview.ListID == nil {
+		return nil, fmt.Errorf("%w: %s", ErrListNotFound, preview.List)
+	}
+
+	return s.CreateTodo(TodoInput{
+		Title:      preview.Title,
+		Priority:   preview.Priority,
+		DueDate:    preview.DueDate,
+		Recurrence: preview.Recurrence,
+		ListID:     preview.ListID,
+		Tags:       preview.Tags,
+		Actor:      actor,
+	})
+}
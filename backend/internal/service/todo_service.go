@@ -1,45 +1,196 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"sort"
 	"testbox/internal/cache"
 	"testbox/internal/messaging"
 	"testbox/internal/models"
+	"testbox/internal/recurrence"
 	"testbox/internal/repository"
+	"testbox/internal/scheduler"
+	"testbox/internal/workflow"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// TodoInput 는 Todo 생성/수정 시 전달되는 필드 묶음입니다
+type TodoInput struct {
+	Title      string
+	Content    string
+	Completed  bool   // Status 가 비어 있을 때만 사용 (하위 호환)
+	Status     string // 워크플로 상태 (빈 문자열이면 생성 시 초기 상태, 수정 시 Completed 로 결정)
+	Priority   int
+	StartDate  *time.Time
+	DueDate    *time.Time
+	ReminderAt *time.Time
+	Recurrence string     // 정규화된 RRULE 문자열 (빈 문자열이면 반복 없음)
+	ListID     *string    // 소속 목록 (nil 이면 목록 없음)
+	Tags       []string   // 태그 이름 (수정 시 nil 이면 기존 태그 유지)
+	Keep       KeepFields // 수정 시 기존 값을 유지할 일정 필드
+	Actor      string     // 변경한 사용자 (X-User-ID, 수정 이력에 기록)
+}
+
+// KeepFields 는 수정 요청에서 생략되어 기존 값을 유지할 일정 필드를 표시합니다
+type KeepFields struct {
+	Priority   bool
+	StartDate  bool
+	DueDate    bool
+	ReminderAt bool
+	Recurrence bool
+}
+
+// ErrTodoNotFound 는 지정한 Todo가 존재하지 않을 때 반환됩니다
+var ErrTodoNotFound = errors.New("Todo를 찾을 수 없습니다")
+
+// ErrInvalidSchedule 은 마감일이 시작일보다 빠를 때 반환됩니다
+var ErrInvalidSchedule = errors.New("마감일은 시작일보다 빠를 수 없습니다")
+
+// ErrListNotFound 는 지정한 목록이 존재하지 않을 때 반환됩니다
+var ErrListNotFound = errors.New("목록을 찾을 수 없습니다")
+
+// ErrInvalidStatus 는 워크플로에 없는 상태를 지정했을 때 반환됩니다
+var ErrInvalidStatus = errors.New("알 수 없는 상태입니다")
+
+// ErrInvalidTransition 은 워크플로가 허용하지 않는 상태 전환일 때 반환됩니다
+var ErrInvalidTransition = errors.New("허용되지 않는 상태 전환입니다")
+
+// ErrOpenBlockers 는 선행 Todo가 아직 완료되지 않은 Todo를 완료하려 할 때 반환됩니다
+var ErrOpenBlockers = errors.New("완료되지 않은 선행 Todo가 있습니다")
+
+// ErrDependencyCycle 은 의존 관계를 추가하면 순환이 생길 때 반환됩니다
+var ErrDependencyCycle = errors.New("의존 관계에 순환이 생깁니다")
+
+// ErrDependencyNotFound 는 삭제하려는 의존 관계가 없을 때 반환됩니다
+var ErrDependencyNotFound = errors.New("의존 관계를 찾을 수 없습니다")
+
+// ErrTodoNotInTrash 는 복원하려는 Todo가 휴지통에 없을 때 반환됩니다
+var ErrTodoNotInTrash = errors.New("휴지통에 없는 Todo입니다")
+
+// ErrParentInTrash 는 상위 Todo가 아직 휴지통에 있어 하위 Todo를 복원할 수 없을 때 반환됩니다
+var ErrParentInTrash = errors.New("상위 Todo가 휴지통에 있습니다. 상위 Todo를 먼저 복원하세요")
+
+// ErrSubtaskMove 는 하위 Todo만 따로 다른 목록으로 옮기려 할 때 반환됩니다
+var ErrSubtaskMove = errors.New("하위 Todo는 상위 Todo와 함께만 이동할 수 있습니다")
+
+// ErrDuplicateTodoID 는 순서를 지정하는 ID 목록에 같은 Todo가 두 번 이상 있을 때 반환됩니다
+var ErrDuplicateTodoID = errors.New("중복된 Todo ID가 있습니다")
+
 type TodoService interface {
-	CreateTodo(title, content string) (*models.Todo, error)
+	CreateTodo(input TodoInput) (*models.Todo, error)
+	PreviewQuickAdd(text string, now time.Time) (*QuickAddPreview, error)
+	QuickAdd(text string, now time.Time, actor string) (*models.Todo, error)
 	GetTodo(id string) (*models.Todo, error)
-	GetAllTodos() ([]models.Todo, error)
-	UpdateTodo(id, title, content string, completed bool) (*models.Todo, error)
+	GetTodoDetail(id string) (*models.TodoDetail, error)
+	GetAllTodos(query repository.TodoQuery) (*repository.TodoPage, error)
+	UpdateTodo(id string, input TodoInput) (*models.Todo, error)
+	RevertTodo(id string, number int, actor string) (*models.Todo, error)
+	RestoreSnapshot(id string, snapshot models.TodoSnapshot, actor string) (*models.Todo, error)
+	DiscardOccurrence(id string) error
 	DeleteTodo(id string) error
+	RestoreTodo(id string) (*models.Todo, error)
+	RestoreTodos(ids []string) ([]models.Todo, error)
+	ArchiveTodo(id string) (*models.Todo, error)
+	UnarchiveTodo(id string) (*models.Todo, error)
+	GetArchivedTodos(query repository.TodoQuery) (*repository.TodoPage, error)
+	CreateSubtask(parentID string, input TodoInput) (*models.Todo, error)
+	GetSubtasks(parentID string) ([]models.Todo, error)
+	GetTodoTree(id string) (*models.TodoNode, error)
+	MoveTodo(id string, listID *string) (*models.Todo, error)
+	ReorderTodo(id, beforeID, afterID string) (*models.Todo, error)
+	ReorderTodos(ids []string) ([]models.Todo, error)
+	BulkUpdate(input BulkInput) (*BulkResult, error)
+	RestorePositions(positions map[string]float64) ([]models.Todo, error)
+	ChangeStatus(id, status, actor string) (*models.Todo, error)
+	GetBoard(query repository.TodoQuery) ([]models.BoardColumn, error)
+	AddDependency(id, blockerID string) (*models.TodoDetail, error)
+	RemoveDependency(id, blockerID string) error
 }
 
+// JobTodoReminder 는 마감 알림 예약 작업의 종류입니다
+const JobTodoReminder = "todo_reminder"
+
+// JobTodoArchive 는 오래전에 완료된 Todo를 보관하는 주기 작업의 종류입니다
+const JobTodoArchive = "todo_archive"
+
+// todoArchiveBatch 는 보관 작업이 한 번에 처리하는 Todo 수입니다
+const todoArchiveBatch = 500
+
 type todoService struct {
-	repo     repository.TodoRepository
-	cache    *cache.RedisCache
-	rabbitmq *messaging.RabbitMQ
+	repo         repository.TodoRepository
+	listRepo     repository.ListRepository
+	tagRepo      repository.TagRepository
+	depRepo      repository.DependencyRepository
+	activityRepo repository.ActivityRepository
+	revisions    revisionRecorder
+	cache        *cache.RedisCache
+	rabbitmq     *messaging.RabbitMQ
+	scheduler    *scheduler.Scheduler
+	workflow     *workflow.Workflow
+	reminderLead time.Duration
+	archiveAfter time.Duration
+}
+
+// reminderPayload 는 마감 알림 작업에 저장되는 데이터입니다
+type reminderPayload struct {
+	TodoID string `json:"todo_id"`
 }
 
-func NewTodoService(repo repository.TodoRepository, cache *cache.RedisCache, rabbitmq *messaging.RabbitMQ) TodoService {
-	return &todoService{
-		repo:     repo,
-		cache:    cache,
-		rabbitmq: rabbitmq,
+func NewTodoService(repo repository.TodoRepository, listRepo repository.ListRepository, tagRepo repository.TagRepository, depRepo repository.DependencyRepository, activityRepo repository.ActivityRepository, revisionRepo repository.RevisionRepository, cache *cache.RedisCache, rabbitmq *messaging.RabbitMQ, sched *scheduler.Scheduler, flow *workflow.Workflow, reminderLead, archiveAfter time.Duration) TodoService {
+	s := &todoService{
+		repo:         repo,
+		listRepo:     listRepo,
+		tagRepo:      tagRepo,
+		depRepo:      depRepo,
+		activityRepo: activityRepo,
+		revisions:    revisionRecorder{repo: revisionRepo},
+		cache:        cache,
+		rabbitmq:     rabbitmq,
+		scheduler:    sched,
+		workflow:     flow,
+		reminderLead: reminderLead,
+		archiveAfter: archiveAfter,
+	}
+	sched.Register(JobTodoReminder, s.handleReminder)
+
+	// 완료 후 archiveAfter 가 지난 Todo는 매시간 자동으로 보관합니다 (0이면 사용 안 함)
+	sched.Register(JobTodoArchive, s.handleArchive)
+	if archiveAfter > 0 {
+		if err := sched.ScheduleCron(JobTodoArchive, JobTodoArchive, "@hourly", nil); err != nil {
+			log.Printf("경고: 자동 보관 작업 예약 실패: %v", err)
+		}
+	} else if err := sched.Cancel(JobTodoArchive); err != nil {
+		log.Printf("경고: 자동 보관 작업 취소 실패: %v", err)
 	}
+	return s
 }
 
 // CreateTodo 는 Write-Through 캐싱 전략을 구현합니다
-func (s *todoService) CreateTodo(title, content string) (*models.Todo, error) {
-	todo := &models.Todo{
-		Title:   title,
-		Content: content,
+func (s *todoService) CreateTodo(input TodoInput) (*models.Todo, error) {
+	if err := s.ensureList(input.ListID); err != nil {
+		return nil, err
 	}
 
+	status, err := s.initialStatus(input)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.tagRepo.FindOrCreate(models.NormalizeTagNames(input.Tags))
+	if err != nil {
+		return nil, fmt.Errorf("태그 처리 실패: %w", err)
+	}
+
+	todo := newTodoFromInput(input)
+	todo.Tags = tags
+	setStatus(todo, status)
+
 	// 1. 먼저 데이터베이스에 저장
 	if err := s.repo.Create(todo); err != nil {
 		return nil, fmt.Errorf("Todo 생성 실패: %w", err)
@@ -60,6 +211,16 @@ func (s *todoService) CreateTodo(title, content string) (*models.Todo, error) {
 		log.Printf("경고: 이벤트 발행 실패: %v", err)
 	}
 
+	// 4. 마감 알림 예약
+	s.scheduleReminder(todo)
+
+	// 5. 목록 집계 캐시 무효화
+	s.invalidateListCounts(todo.ListID)
+
+	// 6. 활동 및 수정 이력 기록
+	s.recordActivity(todo.ID, "created", nil)
+	s.revisions.record(models.RevisionOwnerTodo, todo.ID, models.RevisionCreated, input.Actor, nil, models.NewTodoSnapshot(todo))
+
 	log.Printf("✓ Todo 생성 완료: %s", todo.ID)
 	return todo, nil
 }
@@ -80,7 +241,7 @@ func (s *todoService) GetTodo(id string) (*models.Todo, error) {
 	todo, err := s.repo.FindByID(id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("Todo를 찾을 수 없습니다")
+			return nil, ErrTodoNotFound
 		}
 		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
 	}
@@ -93,44 +254,134 @@ func (s *todoService) GetTodo(id string) (*models.Todo, error) {
 	return todo, nil
 }
 
-// GetAllTodos 는 모든 Todo 목록을 조회합니다
-func (s *todoService) GetAllTodos() ([]models.Todo, error) {
+// GetTodoDetail 은 Todo와 함께 선행 Todo(blockers)와 후행 Todo(dependents)를 조회합니다.
+// Todo 자체는 캐시를 사용하고 의존 관계는 항상 데이터베이스에서 조회합니다.
+func (s *todoService) GetTodoDetail(id string) (*models.TodoDetail, error) {
+	todo, err := s.GetTodo(id)
+	if err != nil {
+		return nil, err
+	}
+
+	blockers, err := s.depRepo.FindBlockers(id)
+	if err != nil {
+		return nil, fmt.Errorf("선행 Todo 조회 실패: %w", err)
+	}
+	dependents, err := s.depRepo.FindDependents(id)
+	if err != nil {
+		return nil, fmt.Errorf("후행 Todo 조회 실패: %w", err)
+	}
+
+	return &models.TodoDetail{Todo: *todo, Blockers: blockers, Dependents: dependents}, nil
+}
+
+// GetAllTodos 는 조건에 맞는 Todo 목록을 커서 기반 페이지 단위로 조회합니다
+func (s *todoService) GetAllTodos(query repository.TodoQuery) (*repository.TodoPage, error) {
 	// 목록 조회는 데이터베이스에서 직접 조회합니다
 	// 리스트 캐싱은 복잡하고 이 사례에서는 효율적이지 않습니다
-	todos, err := s.repo.FindAll()
+	page, err := s.repo.FindPage(query)
 	if err != nil {
 		return nil, fmt.Errorf("Todo 목록 조회 실패: %w", err)
 	}
-	return todos, nil
+	return page, nil
 }
 
 // UpdateTodo 는 Write-Through 캐싱 전략을 구현합니다
-func (s *todoService) UpdateTodo(id, title, content string, completed bool) (*models.Todo, error) {
+func (s *todoService) UpdateTodo(id string, input TodoInput) (*models.Todo, error) {
+	return s.updateTodo(id, input, models.RevisionUpdated)
+}
+
+// RevertTodo 는 주어진 수정 이력의 내용으로 Todo를 되돌립니다.
+// 상태 전환 규칙은 일반 수정과 같이 적용되며, 되돌린 결과도 새 이력으로 기록됩니다.
+func (s *todoService) RevertTodo(id string, number int, actor string) (*models.Todo, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+
+	var snapshot models.TodoSnapshot
+	if err := s.revisions.snapshot(models.RevisionOwnerTodo, id, number, &snapshot); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.updateTodo(id, snapshotInput(snapshot, actor), models.RevisionReverted)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordActivity(id, "reverted", map[string]int{"revision": number})
+	return todo, nil
+}
+
+// RestoreSnapshot 는 되돌리기 토큰에 저장된 이전 내용으로 Todo를 되돌립니다.
+// 직전 상태로 그대로 돌아가는 것이므로 워크플로 전환 규칙과 선행 Todo 검사는 적용하지 않습니다.
+// 완료로 생성된 반복 Todo의 다음 회차는 되돌리기 서비스가 DiscardOccurrence 로 따로 제거합니다.
+func (s *todoService) RestoreSnapshot(id string, snapshot models.TodoSnapshot, actor string) (*models.Todo, error) {
+	todo, err := s.updateTodo(id, snapshotInput(snapshot, actor), models.RevisionUndone)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordActivity(id, "undone", nil)
+	return todo, nil
+}
+
+// updateTodo 는 Todo를 수정하고 주어진 동작으로 수정 이력을 남깁니다
+func (s *todoService) updateTodo(id string, input TodoInput, action string) (*models.Todo, error) {
 	// 1. 기존 Todo 조회
 	todo, err := s.repo.FindByID(id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("Todo를 찾을 수 없습니다")
+			return nil, ErrTodoNotFound
 		}
 		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
 	}
 
-	// 2. 필드 업데이트
-	todo.Title = title
-	todo.Content = content
-	todo.Completed = completed
+	// 2. 상태 전환 검증 (되돌리기는 직전 상태로 돌아가므로 상태 존재 여부만 확인)
+	fromStatus := todo.Status
+	var toStatus string
+	if action == models.RevisionUndone {
+		if !s.workflow.Has(input.Status) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidStatus, input.Status)
+		}
+		toStatus = input.Status
+	} else if toStatus, err = s.nextStatus(todo, input); err != nil {
+		return nil, err
+	}
+
+	// 3. 필드 업데이트
+	before := models.NewTodoSnapshot(todo)
+	wasCompleted := todo.Completed
+	setStatus(todo, toStatus)
+	if err := applyInput(todo, input); err != nil {
+		return nil, err
+	}
 
-	// 3. 데이터베이스에 저장
+	// 4. 데이터베이스에 저장
 	if err := s.repo.Update(todo); err != nil {
 		return nil, fmt.Errorf("Todo 업데이트 실패: %w", err)
 	}
 
-	// 4. Write-Through: 즉시 캐시 업데이트
+	if input.Tags != nil {
+		tags, err := s.tagRepo.FindOrCreate(models.NormalizeTagNames(input.Tags))
+		if err != nil {
+			return nil, fmt.Errorf("태그 처리 실패: %w", err)
+		}
+		if err := s.repo.ReplaceTags(todo, tags); err != nil {
+			return nil, fmt.Errorf("태그 업데이트 실패: %w", err)
+		}
+		todo.Tags = tags
+	}
+
+	s.revisions.record(models.RevisionOwnerTodo, todo.ID, action, input.Actor, before, models.NewTodoSnapshot(todo))
+
+	// 5. Write-Through: 즉시 캐시 업데이트
 	if err := s.cache.SetTodo(todo); err != nil {
 		log.Printf("경고: 캐시 업데이트 실패: %v", err)
 	}
 
-	// 5. 이벤트 발행
+	// 6. 이벤트 발행 (상태가 바뀌었다면 상태 변경 이벤트도 함께)
 	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
 		Action: "updated",
 		TodoID: todo.ID,
@@ -138,31 +389,1285 @@ func (s *todoService) UpdateTodo(id, title, content string, completed bool) (*mo
 	}); err != nil {
 		log.Printf("경고: 이벤트 발행 실패: %v", err)
 	}
+	s.publishStatusChange(todo, fromStatus)
+
+	// 7. 완료 여부 변경에 따른 후속 처리 (롤업, 다음 회차, 알림, 목록 집계)
+	s.afterCompletionChange(todo, wasCompleted)
 
 	log.Printf("✓ Todo 업데이트 완료: %s", todo.ID)
 	return todo, nil
 }
 
-// DeleteTodo 는 Todo를 삭제하고 캐시를 무효화합니다
+// DiscardOccurrence 는 완료로 생성된 반복 Todo의 다음 회차를 하위 Todo와 함께 영구 삭제합니다 (완료 되돌리기).
+// 휴지통에 남은 회차도 이미 생성된 것으로 보므로, 다시 완료할 때 새로 생성되도록 휴지통을 거치지 않습니다.
+// 이미 삭제된 회차면 아무것도 하지 않습니다.
+func (s *todoService) DiscardOccurrence(id string) error {
+	subtree, err := s.repo.FindSubtree(id)
+	if err != nil {
+		return fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+	if len(subtree) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(subtree))
+	var parentID, listID *string
+	for i, todo := range subtree {
+		ids[i] = todo.ID
+		if todo.ID == id {
+			parentID = todo.ParentID
+			listID = todo.ListID
+		}
+	}
+
+	if err := s.repo.PurgeByIDs(ids); err != nil {
+		return fmt.Errorf("반복 Todo 삭제 실패: %w", err)
+	}
+	if err := s.cache.DeleteTodos(ids); err != nil {
+		log.Printf("경고: 캐시 삭제 실패: %v", err)
+	}
+	for _, todoID := range ids {
+		if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+			Action: "deleted",
+			TodoID: todoID,
+			Data:   map[string]bool{"discarded": true},
+		}); err != nil {
+			log.Printf("경고: 이벤트 발행 실패: %v", err)
+		}
+		if err := s.scheduler.Cancel(reminderKey(todoID)); err != nil {
+			log.Printf("경고: 알림 예약 취소 실패: %v", err)
+		}
+	}
+
+	if parentID != nil {
+		s.rollupCompletion(*parentID)
+	}
+	s.invalidateListCounts(listID)
+
+	log.Printf("✓ 반복 Todo 다음 회차 삭제 완료: %s", id)
+	return nil
+}
+
+// DeleteTodo 는 Todo와 모든 하위 Todo를 휴지통으로 옮기고 캐시를 무효화합니다
 func (s *todoService) DeleteTodo(id string) error {
-	// 1. 데이터베이스에서 삭제
-	if err := s.repo.Delete(id); err != nil {
+	// 1. 삭제 대상 서브트리 조회
+	subtree, err := s.repo.FindSubtree(id)
+	if err != nil {
+		return fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+	if len(subtree) == 0 {
+		return ErrTodoNotFound
+	}
+
+	ids := make([]string, len(subtree))
+	var parentID, listID *string
+	for i, todo := range subtree {
+		ids[i] = todo.ID
+		if todo.ID == id {
+			parentID = todo.ParentID
+			listID = todo.ListID
+		}
+	}
+
+	// 2. 서브트리 전체를 휴지통으로 이동 (soft delete, 같은 deleted_at 으로 기록)
+	if err := s.repo.DeleteByIDs(ids); err != nil {
 		return fmt.Errorf("Todo 삭제 실패: %w", err)
 	}
 
-	// 2. 캐시 무효화
-	if err := s.cache.DeleteTodo(id); err != nil {
+	// 3. 캐시 무효화
+	if err := s.cache.DeleteTodos(ids); err != nil {
 		log.Printf("경고: 캐시 삭제 실패: %v", err)
 	}
 
-	// 3. 이벤트 발행
+	// 4. 삭제된 각 Todo에 대해 이벤트 발행
+	for _, todoID := range ids {
+		event := messaging.TodoEvent{
+			Action: "deleted",
+			TodoID: todoID,
+		}
+		if todoID != id {
+			event.Data = map[string]string{"root_id": id}
+		}
+		if err := s.rabbitmq.PublishEvent(event); err != nil {
+			log.Printf("경고: 이벤트 발행 실패: %v", err)
+		}
+	}
+
+	// 5. 예약된 마감 알림 취소
+	for _, todoID := range ids {
+		if err := s.scheduler.Cancel(reminderKey(todoID)); err != nil {
+			log.Printf("경고: 알림 예약 취소 실패: %v", err)
+		}
+	}
+
+	// 6. 남은 형제 기준으로 상위 Todo 완료 상태 재계산
+	if parentID != nil {
+		s.rollupCompletion(*parentID)
+	}
+
+	// 7. 목록 집계 캐시 무효화 (서브트리는 항상 같은 목록에 속합니다)
+	s.invalidateListCounts(listID)
+
+	log.Printf("✓ Todo 삭제 완료: %s (하위 포함 %d개)", id, len(ids))
+	return nil
+}
+
+// RestoreTodo 는 휴지통의 Todo를 함께 삭제된 하위 Todo와 함께 복원합니다.
+// 캐시를 다시 채우고 각 Todo에 대해 "restored" 이벤트를 발행합니다.
+func (s *todoService) RestoreTodo(id string) (*models.Todo, error) {
+	restored, err := s.RestoreTodos([]string{id})
+	if err != nil {
+		return nil, err
+	}
+	return &restored[0], nil
+}
+
+// RestoreTodos 는 휴지통의 여러 Todo를 함께 삭제된 하위 Todo와 함께 한 번에 복원합니다.
+// 하나라도 복원할 수 없으면 아무것도 복원하지 않으며, 복원한 Todo를 ids 순서대로 반환합니다.
+func (s *todoService) RestoreTodos(ids []string) ([]models.Todo, error) {
+	// 1. 함께 삭제된 서브트리 조회 (다른 Todo의 서브트리에 이미 포함된 Todo는 건너뜁니다)
+	byID := make(map[string]*models.Todo)
+	var roots []*models.Todo
+	var restoreIDs []string
+	for _, id := range ids {
+		if byID[id] != nil {
+			continue
+		}
+		subtree, err := s.repo.FindTrashedSubtree(id)
+		if err != nil {
+			return nil, fmt.Errorf("휴지통 조회 실패: %w", err)
+		}
+		if len(subtree) == 0 {
+			return nil, ErrTodoNotInTrash
+		}
+		for i := range subtree {
+			todo := &subtree[i]
+			todo.DeletedAt = gorm.DeletedAt{}
+			if byID[todo.ID] == nil {
+				byID[todo.ID] = todo
+				restoreIDs = append(restoreIDs, todo.ID)
+			}
+		}
+		roots = append(roots, byID[id])
+	}
+
+	// 2. 상위 Todo가 휴지통에 남아 있으면 복원할 수 없습니다
+	for _, root := range roots {
+		if root.ParentID == nil || byID[*root.ParentID] != nil {
+			continue
+		}
+		if _, err := s.repo.FindByID(*root.ParentID); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, ErrParentInTrash
+			}
+			return nil, fmt.Errorf("상위 Todo 조회 실패: %w", err)
+		}
+	}
+
+	// 3. 데이터베이스에서 한 번에 복원
+	if err := s.repo.Restore(restoreIDs); err != nil {
+		return nil, fmt.Errorf("Todo 복원 실패: %w", err)
+	}
+
+	// 4. 캐시 다시 채우기, 이벤트 발행, 마감 알림 재예약
+	for _, id := range restoreIDs {
+		todo := byID[id]
+		if err := s.cache.SetTodo(todo); err != nil {
+			log.Printf("경고: 캐시 업데이트 실패: %v", err)
+		}
+		if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+			Action: "restored",
+			TodoID: todo.ID,
+			Data:   todo,
+		}); err != nil {
+			log.Printf("경고: 이벤트 발행 실패: %v", err)
+		}
+		s.scheduleReminder(todo)
+	}
+
+	// 5. 상위 Todo 완료 상태 재계산, 목록 집계 캐시 무효화, 활동 기록
+	//    (상위 Todo와 함께 복원된 Todo는 상위 Todo의 서브트리로 처리합니다)
+	for _, root := range roots {
+		if root.ParentID != nil {
+			if byID[*root.ParentID] != nil {
+				continue
+			}
+			s.rollupCompletion(*root.ParentID)
+		}
+		s.invalidateListCounts(root.ListID)
+		s.recordActivity(root.ID, "restored", nil)
+	}
+
+	restored := make([]models.Todo, len(ids))
+	for i, id := range ids {
+		restored[i] = *byID[id]
+	}
+	log.Printf("✓ Todo 복원 완료: %d개 (하위 포함 %d개)", len(ids), len(restoreIDs))
+	return restored, nil
+}
+
+// ArchiveTodo 는 Todo와 모든 하위 Todo를 보관합니다.
+// 보관된 Todo는 삭제되지 않으며 기본 목록 조회에서만 제외됩니다.
+func (s *todoService) ArchiveTodo(id string) (*models.Todo, error) {
+	return s.setArchived(id, true, false)
+}
+
+// UnarchiveTodo 는 Todo와 모든 하위 Todo를 보관 상태에서 꺼냅니다
+func (s *todoService) UnarchiveTodo(id string) (*models.Todo, error) {
+	return s.setArchived(id, false, false)
+}
+
+// GetArchivedTodos 는 보관된 Todo를 커서 기반 페이지 단위로 조회합니다
+func (s *todoService) GetArchivedTodos(query repository.TodoQuery) (*repository.TodoPage, error) {
+	query.Archived = repository.ArchivedOnly
+	page, err := s.repo.FindPage(query)
+	if err != nil {
+		return nil, fmt.Errorf("보관된 Todo 목록 조회 실패: %w", err)
+	}
+	return page, nil
+}
+
+// setArchived 는 서브트리 전체의 보관 상태를 바꾸고 바뀐 Todo마다 이벤트를 발행합니다.
+// auto 는 자동 보관 작업이 호출했음을 이벤트와 활동 기록에 남깁니다.
+func (s *todoService) setArchived(id string, archived, auto bool) (*models.Todo, error) {
+	// 1. 대상 서브트리 조회
+	subtree, err := s.repo.FindSubtree(id)
+	if err != nil {
+		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+	if len(subtree) == 0 {
+		return nil, ErrTodoNotFound
+	}
+
+	// 2. 보관 상태가 실제로 바뀌는 Todo만 골라 저장
+	var archivedAt *time.Time
+	action := "unarchived"
+	if archived {
+		now := time.Now()
+		archivedAt = &now
+		action = "archived"
+	}
+
+	var root *models.Todo
+	var changed []*models.Todo
+	for i := range subtree {
+		todo := &subtree[i]
+		if todo.ID == id {
+			root = todo
+		}
+		if (todo.ArchivedAt != nil) != archived {
+			todo.ArchivedAt = archivedAt
+			changed = append(changed, todo)
+		}
+	}
+	if len(changed) == 0 {
+		return root, nil
+	}
+
+	ids := make([]string, len(changed))
+	for i, todo := range changed {
+		ids[i] = todo.ID
+	}
+	if err := s.repo.SetArchived(ids, archivedAt); err != nil {
+		return nil, fmt.Errorf("Todo 보관 상태 변경 실패: %w", err)
+	}
+
+	// 3. 캐시 업데이트 및 이벤트 발행
+	for _, todo := range changed {
+		if err := s.cache.SetTodo(todo); err != nil {
+			log.Printf("경고: 캐시 업데이트 실패: %v", err)
+		}
+		event := messaging.TodoEvent{Action: action, TodoID: todo.ID}
+		data := map[string]interface{}{}
+		if todo.ID != id {
+			data["root_id"] = id
+		}
+		if auto {
+			data["auto"] = true
+		}
+		if len(data) > 0 {
+			event.Data = data
+		}
+		if err := s.rabbitmq.PublishEvent(event); err != nil {
+			log.Printf("경고: 이벤트 발행 실패: %v", err)
+		}
+	}
+
+	// 4. 활동 기록
+	var details interface{}
+	if auto {
+		details = map[string]bool{"auto": true}
+	}
+	s.recordActivity(id, action, details)
+
+	log.Printf("✓ Todo 보관 상태 변경 완료: %s (%s, %d개)", id, action, len(changed))
+	return root, nil
+}
+
+// CreateSubtask 는 상위 Todo 아래에 하위 Todo를 생성합니다
+func (s *todoService) CreateSubtask(parentID string, input TodoInput) (*models.Todo, error) {
+	// 1. 상위 Todo 존재 확인
+	parent, err := s.repo.FindByID(parentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("상위 %w", ErrTodoNotFound)
+		}
+		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+
+	status, err := s.initialStatus(input)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.tagRepo.FindOrCreate(models.NormalizeTagNames(input.Tags))
+	if err != nil {
+		return nil, fmt.Errorf("태그 처리 실패: %w", err)
+	}
+
+	// 하위 Todo는 항상 상위 Todo와 같은 목록에 속합니다
+	todo := newTodoFromInput(input)
+	todo.Tags = tags
+	setStatus(todo, status)
+	todo.ParentID = &parentID
+	todo.ListID = parent.ListID
+
+	// 2. 데이터베이스에 저장
+	if err := s.repo.Create(todo); err != nil {
+		return nil, fmt.Errorf("하위 Todo 생성 실패: %w", err)
+	}
+
+	// 3. Write-Through: 즉시 캐시에 저장
+	if err := s.cache.SetTodo(todo); err != nil {
+		log.Printf("경고: 캐시 저장 실패: %v", err)
+	}
+
+	// 4. 이벤트 발행
 	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
-		Action: "deleted",
-		TodoID: id,
+		Action: "created",
+		TodoID: todo.ID,
+		Data:   todo,
 	}); err != nil {
 		log.Printf("경고: 이벤트 발행 실패: %v", err)
 	}
 
-	log.Printf("✓ Todo 삭제 완료: %s", id)
-	return nil
+	// 5. 마감 알림 예약
+	s.scheduleReminder(todo)
+
+	// 6. 미완료 하위 Todo가 추가되었으므로 상위 완료 상태 재계산
+	s.rollupCompletion(parentID)
+	s.invalidateListCounts(todo.ListID)
+	s.recordActivity(todo.ID, "created", map[string]string{"parent_id": parentID})
+	s.revisions.record(models.RevisionOwnerTodo, todo.ID, models.RevisionCreated, input.Actor, nil, models.NewTodoSnapshot(todo))
+
+	log.Printf("✓ 하위 Todo 생성 완료: %s (상위: %s)", todo.ID, parentID)
+	return todo, nil
+}
+
+// GetSubtasks 는 직계 하위 Todo 목록을 조회합니다
+func (s *todoService) GetSubtasks(parentID string) ([]models.Todo, error) {
+	if _, err := s.GetTodo(parentID); err != nil {
+		return nil, err
+	}
+
+	children, err := s.repo.FindChildren(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("하위 Todo 조회 실패: %w", err)
+	}
+	return children, nil
+}
+
+// GetTodoTree 는 Todo와 모든 하위 Todo를 트리 형태로 조회합니다
+func (s *todoService) GetTodoTree(id string) (*models.TodoNode, error) {
+	subtree, err := s.repo.FindSubtree(id)
+	if err != nil {
+		return nil, fmt.Errorf("Todo 트리 조회 실패: %w", err)
+	}
+
+	tree := models.BuildTodoTree(id, subtree)
+	if tree == nil {
+		return nil, ErrTodoNotFound
+	}
+	return tree, nil
+}
+
+// rollupCompletion 은 하위 Todo의 완료 상태를 상위 Todo로 전파합니다.
+// 모든 하위 Todo가 완료되면 상위도 done 이 되고, 하나라도 미완료면 상위는 초기 상태로 돌아갑니다.
+// 시스템이 수행하는 전환이므로 워크플로 전환 규칙은 적용하지 않습니다.
+// 상위가 변경되면 캐시와 이벤트를 갱신하고 그 위로 계속 전파합니다.
+func (s *todoService) rollupCompletion(parentID string) {
+	for {
+		parent, err := s.repo.FindByID(parentID)
+		if err != nil {
+			log.Printf("경고: 상위 Todo 조회 실패: %v", err)
+			return
+		}
+
+		children, err := s.repo.FindChildren(parentID)
+		if err != nil {
+			log.Printf("경고: 하위 Todo 조회 실패: %v", err)
+			return
+		}
+		if len(children) == 0 {
+			return
+		}
+
+		allCompleted := true
+		for _, child := range children {
+			if !child.Completed {
+				allCompleted = false
+				break
+			}
+		}
+		if parent.Completed == allCompleted {
+			return
+		}
+
+		// 선행 Todo가 남아 있으면 하위가 모두 끝나도 자동 완료하지 않습니다
+		if allCompleted {
+			open, err := s.depRepo.CountOpenBlockers(parent.ID)
+			if err != nil {
+				log.Printf("경고: 선행 Todo 조회 실패: %v", err)
+				return
+			}
+			if open > 0 {
+				log.Printf("선행 Todo가 남아 있어 자동 완료를 건너뜁니다: %s", parent.ID)
+				return
+			}
+		}
+
+		fromStatus := parent.Status
+		if allCompleted {
+			setStatus(parent, workflow.Done)
+		} else {
+			setStatus(parent, s.workflow.Initial)
+		}
+		if err := s.repo.Update(parent); err != nil {
+			log.Printf("경고: 상위 Todo 업데이트 실패: %v", err)
+			return
+		}
+
+		if err := s.cache.SetTodo(parent); err != nil {
+			log.Printf("경고: 캐시 업데이트 실패: %v", err)
+		}
+
+		if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+			Action: "updated",
+			TodoID: parent.ID,
+			Data:   parent,
+		}); err != nil {
+			log.Printf("경고: 이벤트 발행 실패: %v", err)
+		}
+		s.publishStatusChange(parent, fromStatus)
+
+		if parent.Completed {
+			s.spawnNextOccurrence(parent)
+		}
+		s.scheduleReminder(parent)
+		s.invalidateListCounts(parent.ListID)
+
+		if parent.ParentID == nil {
+			return
+		}
+		parentID = *parent.ParentID
+	}
+}
+
+// spawnNextOccurrence 는 완료된 반복 Todo의 다음 회차를 생성하고 "created" 이벤트를 발행합니다.
+// 같은 회차가 이미 생성되어 있으면(완료 취소 후 재완료 등) 아무것도 하지 않습니다.
+func (s *todoService) spawnNextOccurrence(todo *models.Todo) {
+	if todo.Recurrence == "" {
+		return
+	}
+
+	rule, err := recurrence.Parse(todo.Recurrence)
+	if err != nil {
+		log.Printf("경고: 반복 규칙 해석 실패 (%s): %v", todo.ID, err)
+		return
+	}
+
+	// 마감일 기준으로 다음 회차를 계산하고, 마감일이 없으면 완료 시점을 기준으로 합니다
+	base := time.Now()
+	if todo.DueDate != nil {
+		base = *todo.DueDate
+	}
+	// 월 반복은 첫 회차의 날짜를 규칙에 고정하여 짧은 달 이후에도 원래 날짜로 돌아오게 합니다
+	rule.Anchor(base)
+	nextDue, ok := rule.Next(base, todo.Occurrence)
+	if !ok {
+		log.Printf("반복 종료: %s (%d회차)", todo.ID, todo.Occurrence)
+		return
+	}
+
+	seriesID := todo.ID
+	if todo.SeriesID != nil {
+		seriesID = *todo.SeriesID
+	}
+
+	exists, err := s.repo.OccurrenceExists(seriesID, todo.Occurrence+1)
+	if err != nil {
+		log.Printf("경고: 반복 회차 조회 실패: %v", err)
+		return
+	}
+	if exists {
+		return
+	}
+
+	next := &models.Todo{
+		Title:      todo.Title,
+		Content:    todo.Content,
+		ParentID:   todo.ParentID,
+		ListID:     todo.ListID,
+		Status:     s.workflow.Initial,
+		Priority:   todo.Priority,
+		StartDate:  shiftTime(todo.StartDate, base, nextDue),
+		DueDate:    &nextDue,
+		ReminderAt: shiftTime(todo.ReminderAt, base, nextDue),
+		Recurrence: rule.String(),
+		Tags:       todo.Tags,
+		SeriesID:   &seriesID,
+		Occurrence: todo.Occurrence + 1,
+	}
+
+	if err := s.repo.Create(next); err != nil {
+		log.Printf("경고: 다음 반복 Todo 생성 실패: %v", err)
+		return
+	}
+
+	if err := s.cache.SetTodo(next); err != nil {
+		log.Printf("경고: 캐시 저장 실패: %v", err)
+	}
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "created",
+		TodoID: next.ID,
+		Data:   next,
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	s.scheduleReminder(next)
+	s.invalidateListCounts(next.ListID)
+	s.recordActivity(next.ID, "created", map[string]interface{}{
+		"series_id":  seriesID,
+		"occurrence": next.Occurrence,
+	})
+
+	log.Printf("✓ 다음 반복 Todo 생성 완료: %s (%d회차)", next.ID, next.Occurrence)
+}
+
+// MoveTodo 는 Todo와 모든 하위 Todo를 다른 목록으로 이동합니다 (listID 가 nil 이면 목록 없음)
+func (s *todoService) MoveTodo(id string, listID *string) (*models.Todo, error) {
+	// 1. 대상 목록 및 Todo 확인
+	if err := s.ensureList(listID); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+	if todo.ParentID != nil {
+		return nil, ErrSubtaskMove
+	}
+
+	subtree, err := s.repo.FindSubtree(id)
+	if err != nil {
+		return nil, fmt.Errorf("Todo 트리 조회 실패: %w", err)
+	}
+	ids := make([]string, len(subtree))
+	for i := range subtree {
+		ids[i] = subtree[i].ID
+	}
+
+	// 2. 서브트리 전체를 새 목록으로 이동
+	fromListID := todo.ListID
+	if err := s.repo.MoveToList(ids, listID); err != nil {
+		return nil, fmt.Errorf("Todo 이동 실패: %w", err)
+	}
+	todo.ListID = listID
+
+	// 3. 캐시 갱신: 이동한 Todo 들은 무효화하고 루트는 다시 저장
+	if err := s.cache.DeleteTodos(ids); err != nil {
+		log.Printf("경고: 캐시 삭제 실패: %v", err)
+	}
+	if err := s.cache.SetTodo(todo); err != nil {
+		log.Printf("경고: 캐시 업데이트 실패: %v", err)
+	}
+	s.invalidateListCounts(fromListID, listID)
+
+	// 4. 이벤트 발행
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "moved",
+		TodoID: todo.ID,
+		Data: map[string]interface{}{
+			"from_list_id": fromListID,
+			"to_list_id":   listID,
+			"todo_ids":     ids,
+		},
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	// 5. 활동 기록
+	s.recordActivity(todo.ID, "moved", map[string]interface{}{
+		"from_list_id": fromListID,
+		"to_list_id":   listID,
+	})
+
+	log.Printf("✓ Todo 이동 완료: %s (%d개)", todo.ID, len(ids))
+	return todo, nil
+}
+
+// ReorderTodo 는 Todo를 beforeID 바로 앞 또는 afterID 바로 뒤로 옮깁니다.
+// 이웃한 두 위치의 중간값을 사용하므로 이동한 Todo 한 건만 갱신됩니다.
+func (s *todoService) ReorderTodo(id, beforeID, afterID string) (*models.Todo, error) {
+	todo, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+
+	anchorID, before := afterID, false
+	if beforeID != "" {
+		anchorID, before = beforeID, true
+	}
+	if anchorID == id {
+		return todo, nil
+	}
+
+	position, err := s.positionNextTo(anchorID, before, id)
+	if err != nil {
+		return nil, err
+	}
+
+	todo.Position = position
+	if err := s.repo.UpdatePositions(map[string]float64{todo.ID: position}); err != nil {
+		return nil, fmt.Errorf("Todo 순서 변경 실패: %w", err)
+	}
+
+	if err := s.cache.SetTodo(todo); err != nil {
+		log.Printf("경고: 캐시 업데이트 실패: %v", err)
+	}
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "reordered",
+		TodoID: todo.ID,
+		Data: map[string]interface{}{
+			"position":  todo.Position,
+			"before_id": beforeID,
+			"after_id":  afterID,
+		},
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ Todo 순서 변경 완료: %s", todo.ID)
+	return todo, nil
+}
+
+// ReorderTodos 는 주어진 Todo 들이 ids 순서대로 나열되도록 위치를 재배정합니다.
+// 기존에 차지하던 위치 값들을 새 순서대로 나눠 가지므로 다른 Todo 와의 상대 순서는 유지됩니다.
+func (s *todoService) ReorderTodos(ids []string) ([]models.Todo, error) {
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateTodoID, id)
+		}
+		seen[id] = true
+	}
+
+	todos, err := s.repo.FindByIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+	if len(todos) != len(ids) {
+		return nil, ErrTodoNotFound
+	}
+
+	// FindByIDs 는 위치 오름차순이므로 todos[i].Position 이 i 번째 슬롯입니다
+	byID := make(map[string]*models.Todo, len(todos))
+	for i := range todos {
+		byID[todos[i].ID] = &todos[i]
+	}
+	positions := make(map[string]float64, len(ids))
+	ordered := make([]models.Todo, len(ids))
+	slots := make([]float64, len(todos))
+	for i := range todos {
+		slots[i] = todos[i].Position
+	}
+	for i, id := range ids {
+		todo := byID[id]
+		positions[id] = slots[i]
+		todo.Position = slots[i]
+		ordered[i] = *todo
+	}
+
+	if err := s.repo.UpdatePositions(positions); err != nil {
+		return nil, fmt.Errorf("Todo 순서 변경 실패: %w", err)
+	}
+
+	if err := s.cache.DeleteTodos(ids); err != nil {
+		log.Printf("경고: 캐시 삭제 실패: %v", err)
+	}
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "reordered",
+		Data: map[string]interface{}{
+			"todo_ids": ids,
+		},
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ Todo 일괄 순서 변경 완료: %d개", len(ids))
+	return ordered, nil
+}
+
+// RestorePositions 는 Todo 들을 주어진 위치 값으로 되돌립니다 (순서 변경 되돌리기)
+func (s *todoService) RestorePositions(positions map[string]float64) ([]models.Todo, error) {
+	ids := make([]string, 0, len(positions))
+	for id := range positions {
+		ids = append(ids, id)
+	}
+
+	todos, err := s.repo.FindByIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+	if len(todos) != len(ids) {
+		return nil, ErrTodoNotFound
+	}
+
+	if err := s.repo.UpdatePositions(positions); err != nil {
+		return nil, fmt.Errorf("Todo 순서 변경 실패: %w", err)
+	}
+	for i := range todos {
+		todos[i].Position = positions[todos[i].ID]
+	}
+	sort.Slice(todos, func(i, j int) bool { return todos[i].Position < todos[j].Position })
+
+	if err := s.cache.DeleteTodos(ids); err != nil {
+		log.Printf("경고: 캐시 삭제 실패: %v", err)
+	}
+
+	sorted := make([]string, len(todos))
+	for i := range todos {
+		sorted[i] = todos[i].ID
+	}
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "reordered",
+		Data: map[string]interface{}{
+			"todo_ids": sorted,
+		},
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+
+	log.Printf("✓ Todo 순서 복원 완료: %d개", len(todos))
+	return todos, nil
+}
+
+// ChangeStatus 는 워크플로 규칙에 따라 Todo의 상태를 전환합니다
+func (s *todoService) ChangeStatus(id, status, actor string) (*models.Todo, error) {
+	// 1. 기존 Todo 조회 및 전환 검증
+	todo, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+
+	fromStatus := todo.Status
+	if err := s.checkTransition(todo, status); err != nil {
+		return nil, err
+	}
+	if fromStatus == status {
+		return todo, nil
+	}
+
+	// 2. 상태 변경 후 저장
+	before := models.NewTodoSnapshot(todo)
+	wasCompleted := todo.Completed
+	setStatus(todo, status)
+	if err := s.repo.Update(todo); err != nil {
+		return nil, fmt.Errorf("Todo 상태 변경 실패: %w", err)
+	}
+	s.revisions.record(models.RevisionOwnerTodo, todo.ID, models.RevisionUpdated, actor, before, models.NewTodoSnapshot(todo))
+
+	// 3. Write-Through: 즉시 캐시 업데이트
+	if err := s.cache.SetTodo(todo); err != nil {
+		log.Printf("경고: 캐시 업데이트 실패: %v", err)
+	}
+
+	// 4. 상태 변경 이벤트 발행
+	s.publishStatusChange(todo, fromStatus)
+
+	// 5. 완료 여부 변경에 따른 후속 처리
+	s.afterCompletionChange(todo, wasCompleted)
+
+	log.Printf("✓ Todo 상태 변경 완료: %s (%s → %s)", todo.ID, fromStatus, status)
+	return todo, nil
+}
+
+// GetBoard 는 조건에 맞는 Todo를 워크플로 상태별 열로 묶어 반환합니다.
+// 워크플로에서 빠진 상태의 Todo 는 사라지지 않도록 마지막 열들에 모읍니다.
+func (s *todoService) GetBoard(query repository.TodoQuery) ([]models.BoardColumn, error) {
+	todos, err := s.repo.FindAll(query)
+	if err != nil {
+		return nil, fmt.Errorf("보드 조회 실패: %w", err)
+	}
+
+	columns := make([]models.BoardColumn, 0, len(s.workflow.Statuses))
+	index := make(map[string]int, len(s.workflow.Statuses))
+	for _, status := range s.workflow.Statuses {
+		index[status] = len(columns)
+		columns = append(columns, models.BoardColumn{
+			Status:      status,
+			Transitions: s.workflow.Next(status),
+			Items:       []models.Todo{},
+		})
+	}
+
+	for _, todo := range todos {
+		i, ok := index[todo.Status]
+		if !ok {
+			i = len(columns)
+			index[todo.Status] = i
+			columns = append(columns, models.BoardColumn{
+				Status:      todo.Status,
+				Transitions: []string{},
+				Items:       []models.Todo{},
+			})
+		}
+		columns[i].Items = append(columns[i].Items, todo)
+		columns[i].Count++
+	}
+
+	return columns, nil
+}
+
+// AddDependency 는 id Todo가 blockerID Todo의 완료를 기다리도록 의존 관계를 추가합니다
+func (s *todoService) AddDependency(id, blockerID string) (*models.TodoDetail, error) {
+	// 1. 두 Todo 존재 확인
+	if _, err := s.repo.FindByID(id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+	if _, err := s.repo.FindByID(blockerID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("선행 %w", ErrTodoNotFound)
+		}
+		return nil, fmt.Errorf("선행 Todo 조회 실패: %w", err)
+	}
+
+	// 2. 순환 검사 후 의존 관계 저장
+	if err := s.depRepo.Add(id, blockerID); err != nil {
+		if errors.Is(err, repository.ErrDependencyCycle) {
+			return nil, ErrDependencyCycle
+		}
+		return nil, fmt.Errorf("의존 관계 추가 실패: %w", err)
+	}
+
+	// 3. 이벤트 발행 및 활동 기록
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "dependency_added",
+		TodoID: id,
+		Data:   map[string]string{"blocker_id": blockerID},
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+	s.recordActivity(id, "dependency_added", map[string]string{"blocker_id": blockerID})
+
+	log.Printf("✓ 의존 관계 추가 완료: %s → %s", blockerID, id)
+	return s.GetTodoDetail(id)
+}
+
+// RemoveDependency 는 id Todo와 선행 Todo blockerID 사이의 의존 관계를 삭제합니다
+func (s *todoService) RemoveDependency(id, blockerID string) error {
+	removed, err := s.depRepo.Remove(id, blockerID)
+	if err != nil {
+		return fmt.Errorf("의존 관계 삭제 실패: %w", err)
+	}
+	if !removed {
+		return ErrDependencyNotFound
+	}
+
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "dependency_removed",
+		TodoID: id,
+		Data:   map[string]string{"blocker_id": blockerID},
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+	s.recordActivity(id, "dependency_removed", map[string]string{"blocker_id": blockerID})
+
+	log.Printf("✓ 의존 관계 삭제 완료: %s → %s", blockerID, id)
+	return nil
+}
+
+// initialStatus 는 새 Todo의 상태를 결정합니다 (지정하지 않으면 워크플로의 초기 상태)
+func (s *todoService) initialStatus(input TodoInput) (string, error) {
+	if input.Status == "" {
+		return s.workflow.Initial, nil
+	}
+	if !s.workflow.Has(input.Status) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidStatus, input.Status)
+	}
+	return input.Status, nil
+}
+
+// nextStatus 는 수정 요청에 따른 Todo의 다음 상태를 결정하고 전환 가능 여부를 검사합니다.
+// 상태를 지정하지 않은 기존 클라이언트는 completed 값으로 done / 초기 상태 사이를 오갑니다.
+func (s *todoService) nextStatus(todo *models.Todo, input TodoInput) (string, error) {
+	status := input.Status
+	if status == "" {
+		switch {
+		case input.Completed && !todo.Completed:
+			status = workflow.Done
+		case !input.Completed && todo.Completed:
+			status = s.workflow.Initial
+		default:
+			status = todo.Status
+		}
+	}
+
+	if err := s.checkTransition(todo, status); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// checkTransition 은 Todo를 to 상태로 전환할 수 있는지 확인합니다.
+// 워크플로가 허용해야 하고, 완료하려면 모든 선행 Todo가 완료되어 있어야 합니다.
+func (s *todoService) checkTransition(todo *models.Todo, to string) error {
+	if !s.workflow.Has(to) {
+		return fmt.Errorf("%w: %s", ErrInvalidStatus, to)
+	}
+	if !s.workflow.CanTransition(todo.Status, to) {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidTransition, todo.Status, to)
+	}
+
+	if to == workflow.Done && !todo.Completed {
+		open, err := s.depRepo.CountOpenBlockers(todo.ID)
+		if err != nil {
+			return fmt.Errorf("선행 Todo 조회 실패: %w", err)
+		}
+		if open > 0 {
+			return fmt.Errorf("%w (%d개)", ErrOpenBlockers, open)
+		}
+	}
+	return nil
+}
+
+// publishStatusChange 는 상태가 바뀐 경우 이전/이후 상태를 담은 "status_changed" 이벤트를 발행하고
+// 활동 기록에 남깁니다
+func (s *todoService) publishStatusChange(todo *models.Todo, from string) {
+	if todo.Status == from {
+		return
+	}
+	if err := s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "status_changed",
+		TodoID: todo.ID,
+		Data: map[string]interface{}{
+			"from":    from,
+			"to":      todo.Status,
+			"list_id": todo.ListID,
+		},
+	}); err != nil {
+		log.Printf("경고: 이벤트 발행 실패: %v", err)
+	}
+	s.recordActivity(todo.ID, "status_changed", map[string]string{"from": from, "to": todo.Status})
+}
+
+// recordActivity 는 Todo 활동 타임라인에 변경 내역을 남깁니다 (실패해도 요청은 성공 처리)
+func (s *todoService) recordActivity(todoID, action string, data interface{}) {
+	activity := &models.Activity{TodoID: todoID, Action: action}
+	if data != nil {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			log.Printf("경고: 활동 데이터 인코딩 실패: %v", err)
+			return
+		}
+		activity.Data = string(encoded)
+	}
+	if err := s.activityRepo.Create(activity); err != nil {
+		log.Printf("경고: 활동 기록 실패: %v", err)
+	}
+}
+
+// afterCompletionChange 는 Todo 저장 후 완료 여부 변화에 따른 후속 작업을 수행합니다
+func (s *todoService) afterCompletionChange(todo *models.Todo, wasCompleted bool) {
+	// 1. 반복 Todo가 완료되면 다음 회차 생성
+	if !wasCompleted && todo.Completed {
+		s.spawnNextOccurrence(todo)
+	}
+
+	// 2. 완료 상태가 바뀌었다면 상위 Todo로 롤업
+	// (다음 회차는 같은 상위에 속하므로 생성한 뒤에 롤업해야 상위가 완료로 바뀌지 않습니다)
+	if wasCompleted != todo.Completed && todo.ParentID != nil {
+		s.rollupCompletion(*todo.ParentID)
+	}
+
+	// 3. 마감 알림 재예약 (완료되었으면 취소)
+	s.scheduleReminder(todo)
+
+	// 4. 완료 상태가 바뀌면 목록 집계 캐시 무효화
+	if wasCompleted != todo.Completed {
+		s.invalidateListCounts(todo.ListID)
+	}
+}
+
+// positionNextTo 는 anchor 바로 앞(before) 또는 뒤에 들어갈 위치 값을 계산합니다.
+// 간격이 너무 좁아지면 전체 위치를 재배치한 뒤 다시 계산합니다.
+func (s *todoService) positionNextTo(anchorID string, before bool, movingID string) (float64, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		anchor, err := s.repo.FindByID(anchorID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return 0, fmt.Errorf("기준 %w", ErrTodoNotFound)
+			}
+			return 0, fmt.Errorf("Todo 조회 실패: %w", err)
+		}
+
+		neighbor, err := s.repo.FindNeighbor(anchor.Position, before)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return 0, fmt.Errorf("Todo 조회 실패: %w", err)
+		}
+
+		// 이동하는 Todo 자신이 이웃이면 이미 원하는 자리에 있는 것입니다
+		if neighbor != nil && neighbor.ID == movingID {
+			return neighbor.Position, nil
+		}
+
+		if neighbor == nil {
+			if before {
+				return anchor.Position - repository.PositionGap, nil
+			}
+			return anchor.Position + repository.PositionGap, nil
+		}
+
+		mid := (anchor.Position + neighbor.Position) / 2
+		if math.Abs(anchor.Position-neighbor.Position) > repository.MinPositionGap && mid != anchor.Position && mid != neighbor.Position {
+			return mid, nil
+		}
+
+		log.Println("Todo 위치 간격이 좁아 전체 위치를 재배치합니다")
+		if err := s.repo.RebalancePositions(); err != nil {
+			return 0, fmt.Errorf("Todo 위치 재배치 실패: %w", err)
+		}
+		if err := s.cache.InvalidateAll(); err != nil {
+			log.Printf("경고: 캐시 삭제 실패: %v", err)
+		}
+	}
+	return 0, fmt.Errorf("Todo 위치 계산 실패")
+}
+
+// ensureList 는 listID 가 지정된 경우 해당 목록이 존재하는지 확인합니다
+func (s *todoService) ensureList(listID *string) error {
+	if listID == nil {
+		return nil
+	}
+	if _, err := s.listRepo.FindByID(*listID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrListNotFound
+		}
+		return fmt.Errorf("목록 조회 실패: %w", err)
+	}
+	return nil
+}
+
+// invalidateListCounts 는 주어진 목록들의 집계 캐시를 무효화합니다
+func (s *todoService) invalidateListCounts(listIDs ...*string) {
+	var keys []string
+	for _, id := range listIDs {
+		if id != nil {
+			keys = append(keys, *id)
+		}
+	}
+	if err := s.cache.InvalidateListCounts(keys...); err != nil {
+		log.Printf("경고: 목록 집계 캐시 삭제 실패: %v", err)
+	}
+}
+
+// scheduleReminder 는 Todo의 마감 알림을 예약하거나, 필요 없으면 기존 예약을 취소합니다.
+// 알림 시각은 ReminderAt 이 우선이고, 없으면 마감일에서 reminderLead 만큼 앞당긴 시각입니다.
+func (s *todoService) scheduleReminder(todo *models.Todo) {
+	var remindAt *time.Time
+	switch {
+	case todo.ReminderAt != nil:
+		remindAt = todo.ReminderAt
+	case todo.DueDate != nil:
+		t := todo.DueDate.Add(-s.reminderLead)
+		remindAt = &t
+	}
+
+	key := reminderKey(todo.ID)
+	if todo.Completed || remindAt == nil || remindAt.Before(time.Now()) {
+		if err := s.scheduler.Cancel(key); err != nil {
+			log.Printf("경고: 알림 예약 취소 실패: %v", err)
+		}
+		return
+	}
+
+	if err := s.scheduler.ScheduleAt(key, JobTodoReminder, *remindAt, reminderPayload{TodoID: todo.ID}); err != nil {
+		log.Printf("경고: 알림 예약 실패: %v", err)
+	}
+}
+
+// handleReminder 는 예약된 마감 알림을 실행하여 "reminder" 이벤트를 발행합니다
+func (s *todoService) handleReminder(ctx context.Context, job *models.ScheduledJob) error {
+	var payload reminderPayload
+	if err := scheduler.DecodePayload(job, &payload); err != nil {
+		return fmt.Errorf("알림 데이터 해석 실패: %w", err)
+	}
+
+	todo, err := s.repo.FindByID(payload.TodoID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// 이미 삭제된 Todo 는 알릴 필요가 없습니다
+			return nil
+		}
+		return fmt.Errorf("Todo 조회 실패: %w", err)
+	}
+	if todo.Completed {
+		return nil
+	}
+
+	return s.rabbitmq.PublishEvent(messaging.TodoEvent{
+		Action: "reminder",
+		TodoID: todo.ID,
+		Data:   todo,
+	})
+}
+
+// handleArchive 는 완료된 지 archiveAfter 가 지난 최상위 Todo를 보관합니다.
+// 하위 Todo는 따로 보관하지 않고 수동 보관과 같이 상위 Todo와 함께 서브트리 단위로 보관됩니다.
+func (s *todoService) handleArchive(ctx context.Context, job *models.ScheduledJob) error {
+	if s.archiveAfter <= 0 {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-s.archiveAfter)
+	archived := 0
+	for {
+		ids, err := s.repo.FindArchivable(cutoff, todoArchiveBatch)
+		if err != nil {
+			return fmt.Errorf("보관 대상 Todo 조회 실패: %w", err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		for _, todoID := range ids {
+			if _, err := s.setArchived(todoID, true, true); err != nil {
+				return fmt.Errorf("Todo 보관 실패: %w", err)
+			}
+		}
+		archived += len(ids)
+	}
+
+	if archived > 0 {
+		log.Printf("✓ 완료된 Todo %d개 자동 보관 완료", archived)
+	}
+	return nil
+}
+
+// reminderKey 는 Todo별 알림 예약 작업의 고유 키를 반환합니다
+func reminderKey(todoID string) string {
+	return JobTodoReminder + ":" + todoID
+}
+
+// snapshotInput 은 수정 이력 스냅샷을 Todo 수정 입력으로 변환합니다 (태그가 없으면 모두 제거)
+func snapshotInput(snapshot models.TodoSnapshot, actor string) TodoInput {
+	tags := snapshot.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return TodoInput{
+		Title:      snapshot.Title,
+		Content:    snapshot.Content,
+		Status:     snapshot.Status,
+		Priority:   snapshot.Priority,
+		StartDate:  snapshot.StartDate,
+		DueDate:    snapshot.DueDate,
+		ReminderAt: snapshot.ReminderAt,
+		Recurrence: snapshot.Recurrence,
+		Tags:       tags,
+		Actor:      actor,
+	}
+}
+
+// applyInput 은 수정 입력값을 Todo에 반영합니다. Keep 으로 표시된 일정 필드는 기존 값을 유지하므로
+// 합친 결과로 시작일과 마감일의 순서를 다시 검사합니다.
+func applyInput(todo *models.Todo, input TodoInput) error {
+	todo.Title = input.Title
+	todo.Content = input.Content
+	if !input.Keep.Priority {
+		todo.Priority = input.Priority
+	}
+	if !input.Keep.StartDate {
+		todo.StartDate = input.StartDate
+	}
+	if !input.Keep.DueDate {
+		todo.DueDate = input.DueDate
+	}
+	if !input.Keep.ReminderAt {
+		todo.ReminderAt = input.ReminderAt
+	}
+	if !input.Keep.Recurrence {
+		todo.Recurrence = input.Recurrence
+	}
+
+	if todo.StartDate != nil && todo.DueDate != nil && todo.DueDate.Before(*todo.StartDate) {
+		return ErrInvalidSchedule
+	}
+	return nil
+}
+
+// newTodoFromInput 은 입력값으로 새 Todo 모델을 구성합니다
+func newTodoFromInput(input TodoInput) *models.Todo {
+	return &models.Todo{
+		Title:      input.Title,
+		Content:    input.Content,
+		Priority:   input.Priority,
+		StartDate:  input.StartDate,
+		DueDate:    input.DueDate,
+		ReminderAt: input.ReminderAt,
+		Recurrence: input.Recurrence,
+		ListID:     input.ListID,
+		Occurrence: 1,
+	}
+}
+
+// setStatus 는 Todo의 상태를 바꾸고 완료 여부를 상태로부터 다시 계산합니다.
+// 완료되면 완료 시각을 기록하고, 다시 열린 Todo는 보관 상태에서도 꺼냅니다.
+func setStatus(todo *models.Todo, status string) {
+	todo.Status = status
+	todo.Completed = status == workflow.Done
+	switch {
+	case todo.Completed && todo.CompletedAt == nil:
+		now := time.Now()
+		todo.CompletedAt = &now
+	case !todo.Completed:
+		todo.CompletedAt = nil
+		todo.ArchivedAt = nil
+	}
+}
+
+// shiftTime 은 t를 from→to 만큼 이동시킨 값을 반환합니다 (nil이면 nil)
+func shiftTime(t *time.Time, from, to time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	shifted := t.Add(to.Sub(from))
+	return &shifted
 }
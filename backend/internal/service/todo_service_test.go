@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"testbox/internal/models"
+	"testing"
+	"time"
+)
+
+func TestApplyInputKeepsOmittedScheduleFields(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2025, 3, 31, 18, 0, 0, 0, time.UTC)
+	remind := time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC)
+	newDue := time.Date(2025, 4, 15, 18, 0, 0, 0, time.UTC)
+	beforeStart := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	existing := func() *models.Todo {
+		return &models.Todo{
+			Title:      "Pay rent",
+			Priority:   models.PriorityHigh,
+			StartDate:  &start,
+			DueDate:    &due,
+			ReminderAt: &remind,
+			Recurrence: "FREQ=MONTHLY",
+		}
+	}
+	keepAll := KeepFields{Priority: true, StartDate: true, DueDate: true, ReminderAt: true, Recurrence: true}
+
+	tests := []struct {
+		name    string
+		input   TodoInput
+		want    models.Todo
+		wantErr error
+	}{
+		{
+			name:  "title and content only, as sent by the web UI",
+			input: TodoInput{Title: "Pay the rent", Content: "by transfer", Keep: keepAll},
+			want:  models.Todo{Title: "Pay the rent", Content: "by transfer", Priority: models.PriorityHigh, StartDate: &start, DueDate: &due, ReminderAt: &remind, Recurrence: "FREQ=MONTHLY"},
+		},
+		{
+			name:  "explicit values replace",
+			input: TodoInput{Title: "Pay rent", DueDate: &newDue, Keep: KeepFields{Priority: true, StartDate: true, ReminderAt: true, Recurrence: true}},
+			want:  models.Todo{Title: "Pay rent", Priority: models.PriorityHigh, StartDate: &start, DueDate: &newDue, ReminderAt: &remind, Recurrence: "FREQ=MONTHLY"},
+		},
+		{
+			name:  "sent empty values clear",
+			input: TodoInput{Title: "Pay rent"},
+			want:  models.Todo{Title: "Pay rent"},
+		},
+		{
+			name:    "due date before a kept start date",
+			input:   TodoInput{Title: "Pay rent", DueDate: &beforeStart, Keep: KeepFields{Priority: true, StartDate: true, ReminderAt: true, Recurrence: true}},
+			wantErr: ErrInvalidSchedule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := existing()
+			err := applyInput(todo, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("applyInput error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if todo.Title != tt.want.Title || todo.Content != tt.want.Content || todo.Priority != tt.want.Priority ||
+				!sameTime(todo.StartDate, tt.want.StartDate) || !sameTime(todo.DueDate, tt.want.DueDate) ||
+				!sameTime(todo.ReminderAt, tt.want.ReminderAt) || todo.Recurrence != tt.want.Recurrence {
+				t.Errorf("applyInput = %+v, want %+v", *todo, tt.want)
+			}
+		})
+	}
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
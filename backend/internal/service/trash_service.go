@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+	"testbox/internal/scheduler"
+	"time"
+)
+
+// JobTrashPurge is the kind of the job that permanently removes items kept in the trash past the retention period
+const JobTrashPurge = "trash_purge"
+
+// trashPurgeBatch is how many todos or posts one purge query removes
+const trashPurgeBatch = 500
+
+// TrashService lists deleted todos and blog posts and purges them after the retention period.
+// Restoring goes through TodoService and BlogService so caches and events stay consistent.
+type TrashService interface {
+	GetTrash() (*models.Trash, error)
+}
+
+type trashService struct {
+	todoRepo  repository.TodoRepository
+	blogRepo  repository.BlogRepository
+	retention time.Duration
+}
+
+func NewTrashService(todoRepo repository.TodoRepository, blogRepo repository.BlogRepository, sched *scheduler.Scheduler, retention time.Duration) TrashService {
+	s := &trashService{
+		todoRepo:  todoRepo,
+		blogRepo:  blogRepo,
+		retention: retention,
+	}
+
+	// 보관 기간이 지난 휴지통 항목은 매일 영구 삭제합니다
+	sched.Register(JobTrashPurge, s.handlePurge)
+	if err := sched.ScheduleCron(JobTrashPurge, JobTrashPurge, "@daily", nil); err != nil {
+		log.Printf("경고: 휴지통 정리 작업 예약 실패: %v", err)
+	}
+	return s
+}
+
+// GetTrash returns the deleted todos (deletion roots only) and blog posts
+func (s *trashService) GetTrash() (*models.Trash, error) {
+	todos, err := s.todoRepo.FindTrash()
+	if err != nil {
+		return nil, fmt.Errorf("휴지통 Todo 조회 실패: %w", err)
+	}
+	blogs, err := s.blogRepo.FindTrash()
+	if err != nil {
+		return nil, fmt.Errorf("휴지통 블로그 포스트 조회 실패: %w", err)
+	}
+	return &models.Trash{Todos: todos, BlogPosts: blogs}, nil
+}
+
+// purge permanently removes every todo and blog post deleted before the given time
+// and returns how many were removed. Their attachments are left to the attachment cleanup job.
+func (s *trashService) purge(before time.Time) (int, error) {
+	purged := 0
+	for {
+		ids, err := s.todoRepo.FindDeletedBefore(before, trashPurgeBatch)
+		if err != nil {
+			return purged, fmt.Errorf("휴지통 Todo 조회 실패: %w", err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		if err := s.todoRepo.PurgeByIDs(ids); err != nil {
+			return purged, fmt.Errorf("Todo 영구 삭제 실패: %w", err)
+		}
+		purged += len(ids)
+	}
+
+	for {
+		ids, err := s.blogRepo.FindDeletedBefore(before, trashPurgeBatch)
+		if err != nil {
+			return purged, fmt.Errorf("휴지통 블로그 포스트 조회 실패: %w", err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		if err := s.blogRepo.Purge(ids); err != nil {
+			return purged, fmt.Errorf("블로그 포스트 영구 삭제 실패: %w", err)
+		}
+		purged += len(ids)
+	}
+
+	return purged, nil
+}
+
+// handlePurge removes items that have been in the trash longer than the retention period
+func (s *trashService) handlePurge(ctx context.Context, job *models.ScheduledJob) error {
+	purged, err := s.purge(time.Now().Add(-s.retention))
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		log.Printf("✓ 휴지통 항목 %d개 영구 삭제 완료", purged)
+	}
+	return nil
+}
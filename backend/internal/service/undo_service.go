@@ -0,0 +1,270 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"testbox/internal/cache"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Undoable operations
+const (
+	UndoDelete  = "delete"  // todos moved to the trash together with their subtasks
+	UndoUpdate  = "update"  // field, status or tag changes, including completion
+	UndoMove    = "move"    // todos moved to another list
+	UndoReorder = "reorder" // positions changed
+)
+
+// ErrUndoExpired is returned when an undo token is unknown, expired or already used
+var ErrUndoExpired = errors.New("되돌릴 수 없습니다. 되돌리기 시간이 지났거나 이미 사용되었습니다")
+
+// UndoEntry is the state of the affected todos captured before an operation
+type UndoEntry struct {
+	Op    string     `json:"op"`
+	Todos []undoTodo `json:"todos"`
+}
+
+type undoTodo struct {
+	ID       string              `json:"id"`
+	ListID   *string             `json:"list_id"`
+	Position float64             `json:"position"`
+	Snapshot models.TodoSnapshot `json:"snapshot"`
+	Spawned  string              `json:"spawned,omitempty"` // next occurrence of a recurring todo created by the operation
+
+	// seriesID and next are set at capture time when the next occurrence did not exist yet,
+	// so Commit can tell whether the operation created it
+	seriesID string
+	next     int
+}
+
+// Only narrows the entry to the given todos, e.g. the items a bulk operation actually changed.
+// It returns nil when none of them were captured.
+func (e *UndoEntry) Only(ids []string) *UndoEntry {
+	if e == nil {
+		return nil
+	}
+	keep := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		keep[id] = true
+	}
+
+	narrowed := &UndoEntry{Op: e.Op}
+	for _, captured := range e.Todos {
+		if keep[captured.ID] {
+			narrowed.Todos = append(narrowed.Todos, captured)
+		}
+	}
+	if len(narrowed.Todos) == 0 {
+		return nil
+	}
+	return narrowed
+}
+
+// UndoResult is the state of the todos after an undo
+type UndoResult struct {
+	Op    string        `json:"op"`
+	Todos []models.Todo `json:"todos"`
+}
+
+// UndoService lets clients reverse their last destructive operation for a short time.
+// Handlers capture the affected todos before mutating them and commit the capture once the
+// operation succeeded; the returned token can then be redeemed once within the undo window.
+// Reversing goes through TodoService so caches, events and activity stay consistent.
+type UndoService interface {
+	Capture(op string, ids ...string) *UndoEntry
+	Commit(entry *UndoEntry) string
+	Undo(token, actor string) (*UndoResult, error)
+}
+
+type undoService struct {
+	todoRepo    repository.TodoRepository
+	todoService TodoService
+	cache       *cache.RedisCache
+	window      time.Duration
+}
+
+func NewUndoService(todoRepo repository.TodoRepository, todoService TodoService, cache *cache.RedisCache, window time.Duration) UndoService {
+	return &undoService{
+		todoRepo:    todoRepo,
+		todoService: todoService,
+		cache:       cache,
+		window:      window,
+	}
+}
+
+// Capture loads the current state of the todos an operation is about to change.
+// Missing todos are left out; it returns nil when undo is disabled or none can be loaded,
+// in which case the operation itself reports the error and no token is issued.
+func (s *undoService) Capture(op string, ids ...string) *UndoEntry {
+	if s.window <= 0 || len(ids) == 0 {
+		return nil
+	}
+
+	todos, err := s.todoRepo.FindByIDs(ids)
+	if err != nil {
+		log.Printf("경고: 되돌리기 정보 조회 실패: %v", err)
+		return nil
+	}
+	if len(todos) == 0 {
+		return nil
+	}
+
+	entry := &UndoEntry{Op: op, Todos: make([]undoTodo, len(todos))}
+	for i := range todos {
+		entry.Todos[i] = undoTodo{
+			ID:       todos[i].ID,
+			ListID:   todos[i].ListID,
+			Position: todos[i].Position,
+			Snapshot: models.NewTodoSnapshot(&todos[i]),
+		}
+		if op == UndoUpdate && s.nextOccurrencePending(&todos[i]) {
+			entry.Todos[i].seriesID = seriesOf(&todos[i])
+			entry.Todos[i].next = todos[i].Occurrence + 1
+		}
+	}
+	return entry
+}
+
+// nextOccurrencePending reports whether completing the recurring todo would create its next occurrence
+func (s *undoService) nextOccurrencePending(todo *models.Todo) bool {
+	if todo.Recurrence == "" || todo.Completed {
+		return false
+	}
+	exists, err := s.todoRepo.OccurrenceExists(seriesOf(todo), todo.Occurrence+1)
+	return err == nil && !exists
+}
+
+// seriesOf returns the ID of the recurring series the todo belongs to (its own ID for the first occurrence)
+func seriesOf(todo *models.Todo) string {
+	if todo.SeriesID != nil {
+		return *todo.SeriesID
+	}
+	return todo.ID
+}
+
+// Commit stores the captured state and returns its undo token ("" if entry is nil or cannot be stored)
+func (s *undoService) Commit(entry *UndoEntry) string {
+	if entry == nil {
+		return ""
+	}
+
+	// 작업이 생성한 반복 Todo의 다음 회차를 기록해 되돌릴 때 함께 제거합니다
+	for i := range entry.Todos {
+		captured := &entry.Todos[i]
+		if captured.seriesID == "" {
+			continue
+		}
+		next, err := s.todoRepo.FindOccurrence(captured.seriesID, captured.next)
+		if err == nil {
+			captured.Spawned = next.ID
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("경고: 되돌리기 정보 인코딩 실패: %v", err)
+		return ""
+	}
+
+	token := uuid.New().String()
+	if err := s.cache.SetUndo(token, data, s.window); err != nil {
+		log.Printf("경고: 되돌리기 정보 저장 실패: %v", err)
+		return ""
+	}
+	return token
+}
+
+// Undo reverses the operation of the token. A token can be used only once; while the undo
+// runs it is taken out of the cache, and it is put back if undoing fails so the client can retry.
+func (s *undoService) Undo(token, actor string) (*UndoResult, error) {
+	data, ttl, err := s.cache.TakeUndo(token)
+	if err != nil {
+		return nil, fmt.Errorf("되돌리기 정보 조회 실패: %w", err)
+	}
+	if data == nil {
+		return nil, ErrUndoExpired
+	}
+
+	var entry UndoEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("되돌리기 정보 해석 실패: %w", err)
+	}
+	if len(entry.Todos) == 0 {
+		return nil, ErrUndoExpired
+	}
+
+	result, err := s.apply(&entry, actor)
+	if err != nil {
+		if ttl > 0 {
+			if err := s.cache.SetUndo(token, data, ttl); err != nil {
+				log.Printf("경고: 되돌리기 정보 복구 실패: %v", err)
+			}
+		}
+		return nil, err
+	}
+
+	log.Printf("✓ 되돌리기 완료: %s (%d개)", entry.Op, len(result.Todos))
+	return result, nil
+}
+
+// apply reverses the captured operation. Every step restores captured state rather than
+// undoing a delta, so applying an entry again after a partial failure is safe.
+func (s *undoService) apply(entry *UndoEntry, actor string) (*UndoResult, error) {
+	result := &UndoResult{Op: entry.Op, Todos: []models.Todo{}}
+	switch entry.Op {
+	case UndoDelete:
+		ids := make([]string, len(entry.Todos))
+		for i, captured := range entry.Todos {
+			ids[i] = captured.ID
+		}
+		todos, err := s.todoService.RestoreTodos(ids)
+		if err != nil {
+			return nil, err
+		}
+		result.Todos = todos
+
+	case UndoUpdate:
+		for _, captured := range entry.Todos {
+			todo, err := s.todoService.RestoreSnapshot(captured.ID, captured.Snapshot, actor)
+			if err != nil {
+				return nil, err
+			}
+			if captured.Spawned != "" {
+				if err := s.todoService.DiscardOccurrence(captured.Spawned); err != nil {
+					return nil, err
+				}
+			}
+			result.Todos = append(result.Todos, *todo)
+		}
+
+	case UndoMove:
+		for _, captured := range entry.Todos {
+			todo, err := s.todoService.MoveTodo(captured.ID, captured.ListID)
+			if err != nil {
+				return nil, err
+			}
+			result.Todos = append(result.Todos, *todo)
+		}
+
+	case UndoReorder:
+		positions := make(map[string]float64, len(entry.Todos))
+		for _, captured := range entry.Todos {
+			positions[captured.ID] = captured.Position
+		}
+		todos, err := s.todoService.RestorePositions(positions)
+		if err != nil {
+			return nil, err
+		}
+		result.Todos = todos
+
+	default:
+		return nil, fmt.Errorf("알 수 없는 되돌리기 작업: %s", entry.Op)
+	}
+	return result, nil
+}
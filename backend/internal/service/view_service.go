@@ -0,0 +1,309 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"testbox/internal/models"
+	"testbox/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Smart view keys
+const (
+	ViewToday             = "today"
+	ViewUpcoming          = "upcoming"
+	ViewOverdue           = "overdue"
+	ViewCompletedThisWeek = "completed-this-week"
+)
+
+// upcomingDays is how many days after today the Upcoming view covers
+const upcomingDays = 7
+
+// ErrViewNotFound is returned for an unknown smart view or a saved filter of another user
+var ErrViewNotFound = errors.New("보기를 찾을 수 없습니다")
+
+// ErrInvalidFilter is returned when a saved filter has no name or an unsupported option
+var ErrInvalidFilter = errors.New("잘못된 필터입니다")
+
+// smartView is a server-defined view; query builds its filters relative to now
+type smartView struct {
+	key   string
+	name  string
+	query func(now time.Time) repository.TodoQuery
+}
+
+var smartViews = []smartView{
+	{ViewToday, "Today", func(now time.Time) repository.TodoQuery {
+		return repository.TodoQuery{Due: repository.DueToday, Completed: boolPtr(false), SortBy: "due_date", Order: "asc"}
+	}},
+	{ViewUpcoming, "Upcoming", func(now time.Time) repository.TodoQuery {
+		tomorrow := repository.StartOfDay(now).AddDate(0, 0, 1)
+		end := tomorrow.AddDate(0, 0, upcomingDays)
+		return repository.TodoQuery{DueFrom: &tomorrow, DueTo: &end, Completed: boolPtr(false), SortBy: "due_date", Order: "asc"}
+	}},
+	{ViewOverdue, "Overdue", func(now time.Time) repository.TodoQuery {
+		return repository.TodoQuery{Due: repository.DueOverdue, SortBy: "due_date", Order: "asc"}
+	}},
+	{ViewCompletedThisWeek, "Completed this week", func(now time.Time) repository.TodoQuery {
+		startOfWeek := repository.StartOfWeek(now)
+		return repository.TodoQuery{Completed: boolPtr(true), DoneFrom: &startOfWeek, SortBy: "completed_at", Order: "desc"}
+	}},
+}
+
+// ViewService serves the smart views and the saved filters of each user.
+// Both compile to a repository.TodoQuery evaluated at request time, in the caller's time zone.
+type ViewService interface {
+	GetViews(userID string, now time.Time) ([]models.View, error)
+	GetViewTodos(id, userID string, now time.Time, cursor string, limit int) (*repository.TodoPage, error)
+	CreateFilter(userID, name string, filter models.TodoFilter) (*models.SavedFilter, error)
+	GetFilters(userID string) ([]models.SavedFilter, error)
+	UpdateFilter(id, userID, name string, filter models.TodoFilter) (*models.SavedFilter, error)
+	DeleteFilter(id, userID string) error
+}
+
+type viewService struct {
+	repo     repository.FilterRepository
+	todoRepo repository.TodoRepository
+}
+
+func NewViewService(repo repository.FilterRepository, todoRepo repository.TodoRepository) ViewService {
+	return &viewService{
+		repo:     repo,
+		todoRepo: todoRepo,
+	}
+}
+
+// GetViews lists the smart views followed by the user's saved filters, each with its count
+func (s *viewService) GetViews(userID string, now time.Time) ([]models.View, error) {
+	filters, err := s.repo.FindByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("필터 조회 실패: %w", err)
+	}
+
+	views := make([]models.View, 0, len(smartViews)+len(filters))
+	for _, smart := range smartViews {
+		query := smart.query(now)
+		query.Now = now
+		count, err := s.todoRepo.Count(query)
+		if err != nil {
+			return nil, fmt.Errorf("보기 집계 실패: %w", err)
+		}
+		views = append(views, models.View{ID: smart.key, Name: smart.name, Kind: models.ViewSmart, Count: count})
+	}
+
+	for i := range filters {
+		query, err := compileFilter(filters[i].Filter, now)
+		if err != nil {
+			// 저장 이후 지원이 바뀐 옵션이 있어도 나머지 보기는 보여줍니다
+			log.Printf("경고: 필터 해석 실패 (%s): %v", filters[i].ID, err)
+			continue
+		}
+		count, err := s.todoRepo.Count(query)
+		if err != nil {
+			return nil, fmt.Errorf("보기 집계 실패: %w", err)
+		}
+		views = append(views, models.View{
+			ID:     filters[i].ID,
+			Name:   filters[i].Name,
+			Kind:   models.ViewSaved,
+			Count:  count,
+			Filter: &filters[i].Filter,
+		})
+	}
+	return views, nil
+}
+
+// GetViewTodos returns a page of the todos shown by a smart view key or saved filter ID.
+// The page's total is the view's count.
+func (s *viewService) GetViewTodos(id, userID string, now time.Time, cursor string, limit int) (*repository.TodoPage, error) {
+	var query repository.TodoQuery
+	if smart, ok := findSmartView(id); ok {
+		query = smart.query(now)
+		query.Now = now
+	} else {
+		filter, err := s.findFilter(id, userID)
+		if err != nil {
+			return nil, err
+		}
+		query, err = compileFilter(filter.Filter, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query.Cursor = cursor
+	query.Limit = limit
+	page, err := s.todoRepo.FindPage(query)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("보기 조회 실패: %w", err)
+	}
+	return page, nil
+}
+
+// CreateFilter saves a named filter for the user
+func (s *viewService) CreateFilter(userID, name string, filter models.TodoFilter) (*models.SavedFilter, error) {
+	filter, err := validateFilter(name, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := &models.SavedFilter{UserID: userID, Name: strings.TrimSpace(name), Filter: filter}
+	if err := s.repo.Create(saved); err != nil {
+		return nil, fmt.Errorf("필터 생성 실패: %w", err)
+	}
+
+	log.Printf("✓ 필터 생성 완료: %s", saved.ID)
+	return saved, nil
+}
+
+// GetFilters returns the user's saved filters, oldest first
+func (s *viewService) GetFilters(userID string) ([]models.SavedFilter, error) {
+	filters, err := s.repo.FindByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("필터 조회 실패: %w", err)
+	}
+	return filters, nil
+}
+
+// UpdateFilter replaces the name and filter of a saved filter
+func (s *viewService) UpdateFilter(id, userID, name string, filter models.TodoFilter) (*models.SavedFilter, error) {
+	filter, err := validateFilter(name, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := s.findFilter(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	saved.Name = strings.TrimSpace(name)
+	saved.Filter = filter
+	if err := s.repo.Update(saved); err != nil {
+		return nil, fmt.Errorf("필터 수정 실패: %w", err)
+	}
+
+	log.Printf("✓ 필터 수정 완료: %s", id)
+	return saved, nil
+}
+
+// DeleteFilter removes a saved filter
+func (s *viewService) DeleteFilter(id, userID string) error {
+	if _, err := s.findFilter(id, userID); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrViewNotFound
+		}
+		return fmt.Errorf("필터 삭제 실패: %w", err)
+	}
+
+	log.Printf("✓ 필터 삭제 완료: %s", id)
+	return nil
+}
+
+// findFilter loads a saved filter of the user; filters of other users are reported as missing
+func (s *viewService) findFilter(id, userID string) (*models.SavedFilter, error) {
+	filter, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrViewNotFound
+		}
+		return nil, fmt.Errorf("필터 조회 실패: %w", err)
+	}
+	if filter.UserID != userID {
+		return nil, ErrViewNotFound
+	}
+	return filter, nil
+}
+
+func findSmartView(key string) (smartView, bool) {
+	for _, smart := range smartViews {
+		if smart.key == key {
+			return smart, true
+		}
+	}
+	return smartView{}, false
+}
+
+// validateFilter checks the name and options of a filter and returns it with normalized tags
+func validateFilter(name string, filter models.TodoFilter) (models.TodoFilter, error) {
+	if strings.TrimSpace(name) == "" {
+		return filter, fmt.Errorf("%w: name is required", ErrInvalidFilter)
+	}
+	filter.Tags = models.NormalizeTagNames(filter.Tags)
+	if _, err := compileFilter(filter, time.Now()); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// compileFilter turns a stored filter into a repository query evaluated at now
+func compileFilter(filter models.TodoFilter, now time.Time) (repository.TodoQuery, error) {
+	query := repository.TodoQuery{
+		Due:       filter.Due,
+		Priority:  filter.Priority,
+		Completed: filter.Completed,
+		Status:    filter.Status,
+		Archived:  filter.Archived,
+		ListID:    filter.ListID,
+		Tags:      filter.Tags,
+		RootOnly:  filter.RootOnly,
+		Search:    filter.Search,
+		SortBy:    filter.SortBy,
+		Order:     filter.Order,
+		Now:       now,
+	}
+
+	switch query.Due {
+	case "", repository.DueOverdue, repository.DueToday, repository.DueThisWeek:
+	default:
+		return query, fmt.Errorf("%w: due must be one of overdue, today, week", ErrInvalidFilter)
+	}
+	if filter.DueInDays != nil {
+		if *filter.DueInDays < 1 {
+			return query, fmt.Errorf("%w: due_in_days must be positive", ErrInvalidFilter)
+		}
+		from := repository.StartOfDay(now)
+		to := from.AddDate(0, 0, *filter.DueInDays)
+		query.DueFrom, query.DueTo = &from, &to
+	}
+	if query.Priority != nil && !models.IsValidPriority(*query.Priority) {
+		return query, fmt.Errorf("%w: priority must be between 0 and 4", ErrInvalidFilter)
+	}
+	switch query.Archived {
+	case repository.ArchivedExclude, repository.ArchivedInclude, repository.ArchivedOnly:
+	default:
+		return query, fmt.Errorf("%w: archived must be include or only", ErrInvalidFilter)
+	}
+
+	if query.SortBy == "" {
+		query.SortBy = "position"
+	}
+	if !repository.IsValidTodoSort(query.SortBy) {
+		return query, fmt.Errorf("%w: unsupported sort field", ErrInvalidFilter)
+	}
+	if query.Order == "" {
+		// GET /api/todos 와 같이 수동 순서는 오름차순, 나머지는 최신순이 기본입니다
+		query.Order = "desc"
+		if query.SortBy == "position" {
+			query.Order = "asc"
+		}
+	}
+	if query.Order != "asc" && query.Order != "desc" {
+		return query, fmt.Errorf("%w: order must be asc or desc", ErrInvalidFilter)
+	}
+	return query, nil
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,163 @@
+// Package slug turns titles into URL path segments such as "my-first-post".
+//
+// Hangul is romanized with the Revised Romanization of Korean ("개발 일지" → "gaebal-ilji"),
+// Latin letters lose their accents and other letters and digits are kept lowercase;
+// clients percent-encode those in URLs. Everything else separates words with a hyphen.
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MaxLength is the maximum length of a slug in runes, before any collision suffix
+const MaxLength = 80
+
+// Fallback is used for titles without any letters or digits
+const Fallback = "post"
+
+// Make returns the slug of title
+func Make(title string) string {
+	var b strings.Builder
+	hyphen := false
+	runes := []rune(title)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		var word string
+		switch {
+		case isSyllable(r):
+			// 붙어 있는 음절은 연음 규칙을 위해 한 번에 로마자로 바꿉니다
+			j := i
+			for j < len(runes) && isSyllable(runes[j]) {
+				j++
+			}
+			word = romanize(runes[i:j])
+			i = j - 1
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = strings.ToLower(string(r))
+			if base, ok := latinBase[r]; ok {
+				word = base
+			}
+		default:
+			hyphen = b.Len() > 0
+			continue
+		}
+
+		if hyphen {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteString(word)
+	}
+
+	s := truncate(b.String())
+	if s == "" {
+		return Fallback
+	}
+	return s
+}
+
+// WithSuffix returns the n-th candidate for a slug that is already taken: "post", "post-2", "post-3", ...
+func WithSuffix(s string, n int) string {
+	if n <= 1 {
+		return s
+	}
+	return s + "-" + strconv.Itoa(n)
+}
+
+// truncate shortens s to MaxLength runes, preferring to cut at a hyphen
+func truncate(s string) string {
+	runes := []rune(s)
+	if len(runes) <= MaxLength {
+		return s
+	}
+	cut := string(runes[:MaxLength])
+	if i := strings.LastIndexByte(cut, '-'); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.Trim(cut, "-")
+}
+
+// Hangul syllables are composed as 0xAC00 + (initial*21 + medial)*28 + final
+const (
+	syllableBase  = 0xAC00
+	syllableLast  = 0xD7A3
+	medialCount   = 21
+	finalCount    = 28
+	silentInitial = 11 // ㅇ
+	nieunInitial  = 2  // ㄴ
+	rieulInitial  = 5  // ㄹ
+	rieulFinal    = 8  // ㄹ
+)
+
+var (
+	initials = [...]string{"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s", "ss", "", "j", "jj", "ch", "k", "t", "p", "h"}
+	medials  = [...]string{"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i"}
+	// finals as pronounced at the end of a word or before a consonant
+	finals = [...]string{"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "l", "l", "l", "p", "l", "m", "p", "p", "t", "t", "ng", "t", "t", "k", "t", "p", "t"}
+	// finals carried over to a following syllable that starts with a silent ㅇ
+	linkedFinals = [...]string{"", "g", "kk", "gs", "n", "nj", "n", "d", "r", "lg", "lm", "lb", "ls", "lt", "lp", "r", "m", "b", "bs", "s", "ss", "ng", "j", "ch", "k", "t", "p", ""}
+)
+
+func isSyllable(r rune) bool {
+	return r >= syllableBase && r <= syllableLast
+}
+
+// romanize spells a run of Hangul syllables, applying liaison (한국어 → hangugeo)
+// and ㄹ followed by ㄴ or ㄹ as ll (설날 → seollal)
+func romanize(syllables []rune) string {
+	var b strings.Builder
+	for i, r := range syllables {
+		index := int(r - syllableBase)
+		initial, medial, final := index/(medialCount*finalCount), index/finalCount%medialCount, index%finalCount
+
+		if i > 0 {
+			prev := int(syllables[i-1]-syllableBase) % finalCount
+			switch {
+			case prev != 0 && initial == silentInitial:
+				// 앞 음절의 받침이 이어서 발음되므로 이미 적었습니다
+			case prev == rieulFinal && (initial == rieulInitial || initial == nieunInitial):
+				b.WriteString("l")
+			default:
+				b.WriteString(initials[initial])
+			}
+		} else {
+			b.WriteString(initials[initial])
+		}
+		b.WriteString(medials[medial])
+
+		if final == 0 {
+			continue
+		}
+		next := -1
+		if i+1 < len(syllables) {
+			next = int(syllables[i+1]-syllableBase) / (medialCount * finalCount)
+		}
+		if next == silentInitial {
+			b.WriteString(linkedFinals[final])
+		} else {
+			b.WriteString(finals[final])
+		}
+	}
+	return b.String()
+}
+
+// latinBase maps accented Latin letters to their plain spelling
+var latinBase = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'æ': "ae",
+	'À': "a", 'Á': "a", 'Â': "a", 'Ã': "a", 'Ä': "a", 'Å': "a", 'Ā': "a", 'Æ': "ae",
+	'ç': "c", 'Ç': "c", 'č': "c", 'Č': "c", 'ć': "c", 'Ć': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ě': "e",
+	'È': "e", 'É': "e", 'Ê': "e", 'Ë': "e", 'Ē': "e", 'Ě': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i",
+	'Ì': "i", 'Í': "i", 'Î': "i", 'Ï': "i", 'Ī': "i",
+	'ñ': "n", 'Ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'œ': "oe",
+	'Ò': "o", 'Ó': "o", 'Ô': "o", 'Õ': "o", 'Ö': "o", 'Ø': "o", 'Ō': "o", 'Œ': "oe",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u",
+	'Ù': "u", 'Ú': "u", 'Û': "u", 'Ü': "u", 'Ū': "u", 'Ů': "u",
+	'ý': "y", 'ÿ': "y", 'Ý': "y",
+	'ß': "ss", 'ł': "l", 'Ł': "l", 'ř': "r", 'Ř': "r", 'š': "s", 'Š': "s", 'ś': "s",
+	'ž': "z", 'Ž': "z", 'ź': "z", 'ż': "z", 'đ': "d", 'Đ': "d", 'ð': "d", 'þ': "th",
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testbox/internal/config"
+)
+
+// Backends supported by New
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores binary objects such as attachments under opaque keys
+type Storage interface {
+	// Put stores size bytes read from body under key, replacing any existing object
+	Put(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error
+	// Get streams length bytes of the object starting at offset; length < 0 reads to the end
+	Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	// Delete removes the object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates the storage backend selected by cfg.StorageBackend
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageBackend {
+	case BackendLocal:
+		return NewLocalStorage(cfg.StorageLocalDir)
+	case BackendS3:
+		return NewS3Storage(S3Config{
+			Endpoint:     cfg.S3Endpoint,
+			Region:       cfg.S3Region,
+			Bucket:       cfg.S3Bucket,
+			AccessKey:    cfg.S3AccessKey,
+			SecretKey:    cfg.S3SecretKey,
+			UsePathStyle: cfg.S3UsePathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", cfg.StorageBackend)
+	}
+}
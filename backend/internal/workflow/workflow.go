@@ -0,0 +1,149 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Built-in statuses. Done is required in every workflow because a todo's
+// Completed flag is derived from it.
+const (
+	Backlog    = "backlog"
+	Todo       = "todo"
+	InProgress = "in_progress"
+	Blocked    = "blocked"
+	Done       = "done"
+)
+
+// DefaultSpec is the workflow used when none is configured
+const DefaultSpec = "backlog>todo,in_progress,done;" +
+	"todo>backlog,in_progress,blocked,done;" +
+	"in_progress>todo,blocked,done;" +
+	"blocked>todo,in_progress;" +
+	"done>todo,in_progress"
+
+// Workflow is the set of statuses a todo can be in and the transitions allowed between them
+type Workflow struct {
+	Statuses    []string            // board column order
+	Initial     string              // status of newly created and reopened todos
+	Transitions map[string][]string // from -> allowed targets
+}
+
+// Parse reads a spec such as "todo>in_progress,done;in_progress>todo,done;done>todo".
+// Each ";"-separated part lists the statuses reachable from the status before ">".
+// Statuses are ordered by first appearance; initial must be one of them.
+func Parse(spec, initial string) (*Workflow, error) {
+	w := &Workflow{Transitions: map[string][]string{}}
+	seen := map[string]bool{}
+	add := func(status string) error {
+		if !isValidName(status) {
+			return fmt.Errorf("invalid status name: %q", status)
+		}
+		if !seen[status] {
+			seen[status] = true
+			w.Statuses = append(w.Statuses, status)
+		}
+		return nil
+	}
+
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		from, targets, ok := strings.Cut(part, ">")
+		if !ok {
+			return nil, fmt.Errorf("invalid transition rule: %s", part)
+		}
+		from = strings.TrimSpace(from)
+		if err := add(from); err != nil {
+			return nil, err
+		}
+		if _, dup := w.Transitions[from]; dup {
+			return nil, fmt.Errorf("duplicate transition rule for %s", from)
+		}
+
+		w.Transitions[from] = []string{}
+		for _, to := range strings.Split(targets, ",") {
+			to = strings.TrimSpace(to)
+			if to == "" {
+				continue
+			}
+			if err := add(to); err != nil {
+				return nil, err
+			}
+			if to != from {
+				w.Transitions[from] = append(w.Transitions[from], to)
+			}
+		}
+	}
+
+	if !seen[Done] {
+		return nil, fmt.Errorf("workflow must include the %q status", Done)
+	}
+	initial = strings.TrimSpace(initial)
+	if !seen[initial] {
+		return nil, fmt.Errorf("initial status %q is not part of the workflow", initial)
+	}
+	if initial == Done {
+		return nil, fmt.Errorf("initial status must not be %q", Done)
+	}
+	w.Initial = initial
+
+	return w, nil
+}
+
+// Default returns the built-in workflow
+func Default() *Workflow {
+	w, err := Parse(DefaultSpec, Todo)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// Has reports whether status belongs to the workflow
+func (w *Workflow) Has(status string) bool {
+	for _, s := range w.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransition reports whether a todo may move from one status to another.
+// Staying in the same status is always allowed.
+func (w *Workflow) CanTransition(from, to string) bool {
+	if from == to {
+		return w.Has(to)
+	}
+	for _, s := range w.Transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the statuses reachable from the given status
+func (w *Workflow) Next(from string) []string {
+	next := w.Transitions[from]
+	if next == nil {
+		return []string{}
+	}
+	return next
+}
+
+// isValidName accepts lowercase identifiers such as "in_progress"
+func isValidName(s string) bool {
+	if s == "" || len(s) > 32 {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
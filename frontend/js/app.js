@@ -25,13 +25,23 @@ document.addEventListener('DOMContentLoaded', () => {
     });
 });
 
-// Load all todos
+// Load all todos, following next_cursor until the last page
 async function loadTodos() {
     try {
-        const response = await fetch(`${API_BASE_URL}/todos`);
-        if (!response.ok) throw new Error('Failed to fetch todos');
+        const todos = [];
+        let cursor = '';
+        do {
+            const params = new URLSearchParams({ limit: 200 });
+            if (cursor) params.set('cursor', cursor);
+
+            const response = await fetch(`${API_BASE_URL}/todos?${params}`);
+            if (!response.ok) throw new Error('Failed to fetch todos');
+
+            const page = await response.json();
+            todos.push(...page.items);
+            cursor = page.next_cursor || '';
+        } while (cursor);
 
-        const todos = await response.json();
         renderTodos(todos);
     } catch (error) {
         console.error('Error loading todos:', error);
@@ -49,7 +49,7 @@ function renderBlogs(blogs) {
     blogItemsContainer.innerHTML = blogs.map(blog => `
         <div class="blog-item">
             <h3>${escapeHtml(blog.title)}</h3>
-            ${blog.tags ? `<div class="blog-tags">${renderTags(blog.tags)}</div>` : ''}
+            ${blog.tags && blog.tags.length ? `<div class="blog-tags">${renderTags(blog.tags)}</div>` : ''}
             <div class="blog-content">${escapeHtml(blog.content)}</div>
             <div class="todo-meta">
                 작성일: ${new Date(blog.created_at).toLocaleString('ko-KR')}
@@ -64,11 +64,15 @@ function renderBlogs(blogs) {
 }
 
 // Render tags
-function renderTags(tagsString) {
-    if (!tagsString || tagsString.trim() === '') return '';
+function renderTags(tags) {
+    if (!tags || tags.length === 0) return '';
 
-    const tags = tagsString.split(',').map(tag => tag.trim()).filter(tag => tag);
-    return tags.map(tag => `<span class="tag">${escapeHtml(tag)}</span>`).join('');
+    return tags.map(tag => `<span class="tag">${escapeHtml(tag.name)}</span>`).join('');
+}
+
+// Split comma-separated tag input into tag names
+function parseTags(tagsString) {
+    return (tagsString || '').split(',').map(tag => tag.trim()).filter(tag => tag);
 }
 
 // Handle add blog
@@ -78,7 +82,7 @@ async function handleAddBlog(e) {
     const formData = new FormData(e.target);
     const title = formData.get('title');
     const content = formData.get('content');
-    const tags = formData.get('tags') || '';
+    const tags = parseTags(formData.get('tags'));
 
     try {
         const response = await fetch(`${API_BASE_URL}/blogs`, {
@@ -113,7 +117,7 @@ async function openEditModal(id) {
         document.getElementById('editId').value = blog.id;
         document.getElementById('editTitle').value = blog.title;
         document.getElementById('editContent').value = blog.content || '';
-        document.getElementById('editTags').value = blog.tags || '';
+        document.getElementById('editTags').value = (blog.tags || []).map(tag => tag.name).join(', ');
 
         editModal.style.display = 'block';
     } catch (error) {
@@ -130,7 +134,7 @@ async function handleEditBlog(e) {
     const formData = new FormData(e.target);
     const title = formData.get('title');
     const content = formData.get('content');
-    const tags = formData.get('tags') || '';
+    const tags = parseTags(formData.get('tags'));
 
     try {
         const response = await fetch(`${API_BASE_URL}/blogs/${id}`, {